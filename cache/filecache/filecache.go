@@ -213,6 +213,47 @@ func (c *Cache) GetOrCreateBytes(id string, create func() ([]byte, error)) (Item
 
 }
 
+// GetOrCreateBytesOrStale is the same as GetOrCreateBytes, but if create fails
+// and the cache holds an expired entry for id, that entry is returned instead
+// of the error.
+func (c *Cache) GetOrCreateBytesOrStale(id string, create func() ([]byte, error)) (ItemInfo, []byte, error) {
+	id = cleanID(id)
+
+	c.nlocker.Lock(id)
+	defer c.nlocker.Unlock(id)
+
+	info := ItemInfo{Name: id}
+
+	if c.maxAge == 0 {
+		b, err := create()
+		return info, b, err
+	}
+
+	var stale bool
+	if fi, err := c.Fs.Stat(id); err == nil {
+		if !c.isExpired(fi.ModTime()) {
+			b, err := afero.ReadFile(c.Fs, id)
+			return info, b, err
+		}
+		stale = true
+	}
+
+	b, err := create()
+	if err != nil {
+		if stale {
+			if b, rerr := afero.ReadFile(c.Fs, id); rerr == nil {
+				return info, b, nil
+			}
+		}
+		return info, nil, err
+	}
+
+	if err := afero.WriteReader(c.Fs, id, bytes.NewReader(b)); err != nil {
+		return info, nil, err
+	}
+	return info, b, nil
+}
+
 // GetBytes gets the file content with the given id from the cahce, nil if none found.
 func (c *Cache) GetBytes(id string) (ItemInfo, []byte, error) {
 	id = cleanID(id)
@@ -42,6 +42,7 @@ var defaultCacheConfig = Config{
 const (
 	cacheKeyGetJSON = "getjson"
 	cacheKeyGetCSV  = "getcsv"
+	cacheKeyOEmbed  = "oembed"
 	cacheKeyImages  = "images"
 	cacheKeyAssets  = "assets"
 	cacheKeyModules = "modules"
@@ -60,6 +61,7 @@ var defaultCacheConfigs = Configs{
 	},
 	cacheKeyGetJSON: defaultCacheConfig,
 	cacheKeyGetCSV:  defaultCacheConfig,
+	cacheKeyOEmbed:  defaultCacheConfig,
 	cacheKeyImages: {
 		MaxAge: -1,
 		Dir:    resourcesGenDir,
@@ -94,6 +96,12 @@ func (f Caches) GetCSVCache() *Cache {
 	return f[cacheKeyGetCSV]
 }
 
+// OEmbedCache gets the file cache for the oEmbed data fetched by the
+// embedded shortcodes.
+func (f Caches) OEmbedCache() *Cache {
+	return f[cacheKeyOEmbed]
+}
+
 // ImageCache gets the file cache for processed images.
 func (f Caches) ImageCache() *Cache {
 	return f[cacheKeyImages]
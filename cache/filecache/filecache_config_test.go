@@ -60,7 +60,7 @@ dir = "/path/to/c3"
 	decoded, err := DecodeConfig(fs, cfg)
 	assert.NoError(err)
 
-	assert.Equal(5, len(decoded))
+	assert.Equal(6, len(decoded))
 
 	c2 := decoded["getcsv"]
 	assert.Equal("11h0m0s", c2.MaxAge.String())
@@ -105,7 +105,7 @@ dir = "/path/to/c3"
 	decoded, err := DecodeConfig(fs, cfg)
 	assert.NoError(err)
 
-	assert.Equal(5, len(decoded))
+	assert.Equal(6, len(decoded))
 
 	for _, v := range decoded {
 		assert.Equal(time.Duration(0), v.MaxAge)
@@ -132,7 +132,7 @@ func TestDecodeConfigDefault(t *testing.T) {
 
 	assert.NoError(err)
 
-	assert.Equal(5, len(decoded))
+	assert.Equal(6, len(decoded))
 
 	imgConfig := decoded[cacheKeyImages]
 	jsonConfig := decoded[cacheKeyGetJSON]
@@ -44,6 +44,9 @@ dir = "/cache/c"
 [caches.getcsv]
 maxAge = "200ms"
 dir = "/cache/d"
+[caches.oembed]
+maxAge = "200ms"
+dir = "/cache/e"
 [caches.assets]
 maxAge = "200ms"
 dir = ":resourceDir/_gen"
@@ -52,7 +55,7 @@ maxAge = "200ms"
 dir = ":resourceDir/_gen"
 `
 
-	for _, name := range []string{cacheKeyGetCSV, cacheKeyGetJSON, cacheKeyAssets, cacheKeyImages} {
+	for _, name := range []string{cacheKeyGetCSV, cacheKeyGetJSON, cacheKeyOEmbed, cacheKeyAssets, cacheKeyImages} {
 		msg := fmt.Sprintf("cache: %s", name)
 		p := newPathsSpec(t, afero.NewMemMapFs(), configStr)
 		caches, err := NewCaches(p)
@@ -33,6 +33,7 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 
 	"github.com/stretchr/testify/require"
@@ -244,6 +245,46 @@ dir = "/cache/c"
 	wg.Wait()
 }
 
+func TestFileCacheGetOrCreateBytesOrStale(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	cache := NewCache(afero.NewMemMapFs(), 100*time.Millisecond, "")
+
+	failing := func() ([]byte, error) {
+		return nil, errors.New("offline")
+	}
+
+	_, _, err := cache.GetOrCreateBytesOrStale("a", failing)
+	assert.Error(err)
+
+	_, b, err := cache.GetOrCreateBytesOrStale("a", func() ([]byte, error) {
+		return []byte("abc"), nil
+	})
+	assert.NoError(err)
+	assert.Equal("abc", string(b))
+
+	// Not expired, create is not invoked.
+	_, b, err = cache.GetOrCreateBytesOrStale("a", failing)
+	assert.NoError(err)
+	assert.Equal("abc", string(b))
+
+	time.Sleep(150 * time.Millisecond)
+
+	// Expired, the stale entry is used when create fails.
+	_, b, err = cache.GetOrCreateBytesOrStale("a", failing)
+	assert.NoError(err)
+	assert.Equal("abc", string(b))
+
+	// Expired, the entry is replaced when create succeeds.
+	_, b, err = cache.GetOrCreateBytesOrStale("a", func() ([]byte, error) {
+		return []byte("bcd"), nil
+	})
+	assert.NoError(err)
+	assert.Equal("bcd", string(b))
+	assert.Equal("bcd", cache.getString("a"))
+}
+
 func TestCleanID(t *testing.T) {
 	assert := require.New(t)
 	assert.Equal(filepath.FromSlash("a/b/c.txt"), cleanID(filepath.FromSlash("/a/b//c.txt")))
@@ -29,6 +29,7 @@ type Service struct {
 type Config struct {
 	Disqus          Disqus
 	GoogleAnalytics GoogleAnalytics
+	GoogleMaps      GoogleMaps
 	Instagram       Instagram
 	Twitter         Twitter
 	Vimeo           Vimeo
@@ -53,6 +54,20 @@ type GoogleAnalytics struct {
 
 	// Enabling this will make it so the users' IP addresses are anonymized within Google Analytics.
 	AnonymizeIP bool
+
+	// Enabling this will make the Google Analytics 4 templates deny all storage
+	// by default using consent mode, until the site updates the consent.
+	ConsentMode bool
+}
+
+// GoogleMaps holds the privacy configuration settings related to the Google Maps shortcode.
+type GoogleMaps struct {
+	Service `mapstructure:",squash"`
+
+	// If simple mode is enabled, a static map image is fetched from the
+	// Static Maps API instead, and the interactive map is only loaded
+	// when the user clicks it.
+	Simple bool
 }
 
 // Instagram holds the privacy configuration settings related to the Instagram shortcode.
@@ -36,6 +36,10 @@ disable = true
 respectDoNotTrack = true
 anonymizeIP = true
 useSessionStorage = true
+consentMode = true
+[privacy.googleMaps]
+disable = true
+simple = true
 [privacy.instagram]
 disable = true
 simple = true
@@ -63,6 +67,9 @@ simple = true
 	assert.True(pc.GoogleAnalytics.RespectDoNotTrack)
 	assert.True(pc.GoogleAnalytics.AnonymizeIP)
 	assert.True(pc.GoogleAnalytics.UseSessionStorage)
+	assert.True(pc.GoogleAnalytics.ConsentMode)
+	assert.True(pc.GoogleMaps.Disable)
+	assert.True(pc.GoogleMaps.Simple)
 	assert.True(pc.Instagram.Disable)
 	assert.True(pc.Instagram.Simple)
 	assert.True(pc.Twitter.Disable)
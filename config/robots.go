@@ -0,0 +1,46 @@
+// Copyright 2019 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/mitchellh/mapstructure"
+)
+
+const robotsConfigKey = "robots"
+
+// Robots configures the embedded robots.txt template.
+type Robots struct {
+	// The Crawl-delay, in seconds, for all user agents. Zero means no delay.
+	CrawlDelay int
+
+	// Additional rules for named user agents.
+	Rules []RobotsRule
+}
+
+// RobotsRule holds the robots.txt directives for a single user agent.
+type RobotsRule struct {
+	UserAgent  string
+	CrawlDelay int
+	Allow      []string
+	Disallow   []string
+}
+
+// DecodeRobots creates a Robots config from a given Hugo configuration.
+func DecodeRobots(cfg Provider) (r Robots, err error) {
+	m := cfg.GetStringMap(robotsConfigKey)
+
+	err = mapstructure.WeakDecode(m, &r)
+
+	return
+}
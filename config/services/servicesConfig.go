@@ -14,8 +14,10 @@
 package services
 
 import (
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/config"
 	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
 )
 
 const (
@@ -24,14 +26,21 @@ const (
 	disqusShortnameKey = "disqusshortname"
 	googleAnalyticsKey = "googleanalytics"
 	rssLimitKey        = "rssLimit"
+
+	// The per language RSS settings, e.g. languages.fr.rss.
+	languageRSSKey = "rss"
 )
 
 // Config is a privacy configuration for all the relevant services in Hugo.
 type Config struct {
 	Disqus          Disqus
+	Facebook        Facebook
 	GoogleAnalytics GoogleAnalytics
+	GoogleMaps      GoogleMaps
 	Instagram       Instagram
 	Twitter         Twitter
+	GitHub          GitHub
+	OEmbed          OEmbed
 	RSS             RSS
 }
 
@@ -41,12 +50,25 @@ type Disqus struct {
 	Shortname string
 }
 
+// Facebook holds the functional configuration settings related to the Open Graph template.
+type Facebook struct {
+	// The ID of the Facebook app used for Insights, emitted as fb:app_id.
+	AppID string
+}
+
 // GoogleAnalytics holds the functional configuration settings related to the Google Analytics template.
 type GoogleAnalytics struct {
 	// The GA tracking ID.
 	ID string
 }
 
+// GoogleMaps holds the functional configuration settings related to the Google Maps shortcode.
+type GoogleMaps struct {
+	// The API key for the Maps Embed and Static Maps APIs. The simple
+	// variant requires it.
+	APIKey string
+}
+
 // Instagram holds the functional configuration settings related to the Instagram shortcodes.
 type Instagram struct {
 	// The Simple variant of the Instagram is decorated with Bootstrap 4 card classes.
@@ -63,16 +85,106 @@ type Twitter struct {
 	DisableInlineCSS bool
 }
 
+// GitHub holds the functional configuration settings related to the GitHub shortcode.
+type GitHub struct {
+	// An optional API token to get a higher rate limit. It is sent in the
+	// Authorization header of the requests to the GitHub API.
+	Token string
+}
+
+// OEmbed holds the functional configuration settings related to the remote
+// data fetched by the GitHub, Instagram, Twitter and Vimeo shortcodes.
+type OEmbed struct {
+	// By default, a shortcode that fails to fetch its oEmbed data logs a
+	// warning and renders a plain link to the original post. Set this to
+	// fail the build instead, e.g. in CI.
+	FailOnError bool
+
+	// The hosts the shortcodes may fetch oEmbed data from, e.g.
+	// ["twitter.com", "vimeo.com"]. A host also allows its subdomains.
+	// All hosts are allowed if not set.
+	AllowedHosts []string
+}
+
 // RSS holds the functional configuration settings related to the RSS feeds.
 type RSS struct {
 	// Limit the number of pages.
 	Limit int
+
+	// The title of the home page feed. Defaults to the site title. The
+	// other feeds are titled with the page title followed by the site title.
+	Title string
+
+	// The number of words of the content to use for the item descriptions.
+	// The page summary is used if not set.
+	SummaryLength int
+
+	// Whether to use the manual summary, set with the summary divider or
+	// the summary front matter, when a page has one, and the summary
+	// above otherwise. The two are wrapped in a summary-manual and a
+	// summary-auto div.
+	ManualSummary bool
+
+	// The anchor appended to the page permalink in the item's comments link,
+	// e.g. "#comments". Defaults to "#disqus_thread" when Disqus is configured.
+	CommentsAnchor string
+
+	// The layout used for pubDate and lastBuildDate. Defaults to RFC 822
+	// with a numeric zone, "Mon, 02 Jan 2006 15:04:05 -0700", with the
+	// English day and month names RFC 822 requires. The names in a
+	// configured layout are translated to the site language.
+	DateFormat string
+
+	// How to build the item guid, "permalink" (default) or "stable".
+	// A stable guid survives URL changes: it is the page's guid front matter
+	// value if set, else GUIDPrefix followed by the page's file based unique ID.
+	GUID string
+
+	// The prefix for the stable guids, e.g. "tag:example.com,2019:".
+	GUIDPrefix string
+
+	// The number of minutes a feed can be cached before refreshing, emitted
+	// as the channel's ttl.
+	TTL int
+
+	// The hours (0-23, GMT) and days (e.g. "Saturday") aggregators may skip
+	// reading the feed.
+	SkipHours []int
+	SkipDays  []string
+
+	// Maps a language to the language code emitted in the feed when
+	// languageCode isn't set, e.g. "en" to "en-us". The language
+	// itself is used if it isn't in the map.
+	LanguageCodes map[string]string
+
+	// Whether to minify the feeds, even if the site isn't minified.
+	Minify bool
+
+	// Whether to emit the full page content in the items' content:encoded.
+	FullContent bool
+
+	// Whether to resolve the relative URLs in the src, href and srcset
+	// attributes of the full content against the page permalink, as feed
+	// readers have no base URL to resolve them against. Defaults to true.
+	AbsolutizeURLs bool
+
+	// Whether to include draft, future and expired pages. These only
+	// leave out pages, as pages not built, see buildDrafts, buildFuture
+	// and buildExpired, are never listed. All default to true.
+	IncludeDrafts  bool
+	IncludeFuture  bool
+	IncludeExpired bool
 }
 
 // DecodeConfig creates a services Config from a given Hugo configuration.
 func DecodeConfig(cfg config.Provider) (c Config, err error) {
 	m := cfg.GetStringMap(servicesConfigKey)
 
+	c.RSS.IncludeDrafts = true
+	c.RSS.IncludeFuture = true
+	c.RSS.IncludeExpired = true
+	c.RSS.AbsolutizeURLs = true
+
 	err = mapstructure.WeakDecode(m, &c)
 
 	// Keep backwards compatibility.
@@ -88,5 +200,17 @@ func DecodeConfig(cfg config.Provider) (c Config, err error) {
 		c.RSS.Limit = cfg.GetInt(rssLimitKey)
 	}
 
+	// The limit and title can be set per language, e.g. to give a
+	// sparsely translated language a shorter feed.
+	if m := cfg.GetStringMap(languageRSSKey); len(m) > 0 {
+		maps.ToLower(m)
+		if v, found := m["limit"]; found {
+			c.RSS.Limit = cast.ToInt(v)
+		}
+		if v, found := m["title"]; found {
+			c.RSS.Title = cast.ToString(v)
+		}
+	}
+
 	return
 }
@@ -31,12 +31,28 @@ someOtherValue = "foo"
 [services]
 [services.disqus]
 shortname = "DS"
+[services.facebook]
+appID = "fb_app_id"
 [services.googleAnalytics]
 id = "ga_id"
+[services.googleMaps]
+apiKey = "maps_key"
 [services.instagram]
 disableInlineCSS = true
 [services.twitter]
 disableInlineCSS = true
+[services.github]
+token = "gh_token"
+[services.oembed]
+failOnError = true
+allowedHosts = ["twitter.com", "vimeo.com"]
+[services.rss]
+ttl = 60
+includeFuture = false
+skipHours = [0, 1, 2]
+skipDays = ["Saturday", "Sunday"]
+[services.rss.languageCodes]
+en = "en-us"
 `
 	cfg, err := config.FromConfigString(tomlConfig, "toml")
 	assert.NoError(err)
@@ -46,9 +62,22 @@ disableInlineCSS = true
 	assert.NotNil(config)
 
 	assert.Equal("DS", config.Disqus.Shortname)
+	assert.Equal("fb_app_id", config.Facebook.AppID)
 	assert.Equal("ga_id", config.GoogleAnalytics.ID)
+	assert.Equal("maps_key", config.GoogleMaps.APIKey)
 
 	assert.True(config.Instagram.DisableInlineCSS)
+	assert.Equal("gh_token", config.GitHub.Token)
+	assert.True(config.OEmbed.FailOnError)
+	assert.Equal([]string{"twitter.com", "vimeo.com"}, config.OEmbed.AllowedHosts)
+	assert.Equal(60, config.RSS.TTL)
+	assert.True(config.RSS.IncludeDrafts)
+	assert.False(config.RSS.IncludeFuture)
+	assert.True(config.RSS.IncludeExpired)
+	assert.True(config.RSS.AbsolutizeURLs)
+	assert.Equal([]int{0, 1, 2}, config.RSS.SkipHours)
+	assert.Equal([]string{"Saturday", "Sunday"}, config.RSS.SkipDays)
+	assert.Equal(map[string]string{"en": "en-us"}, config.RSS.LanguageCodes)
 }
 
 // Support old root-level GA settings etc.
@@ -67,3 +96,30 @@ func TestUseSettingsFromRootIfSet(t *testing.T) {
 	assert.Equal("ga_root", config.GoogleAnalytics.ID)
 
 }
+
+func TestDecodeLanguageRSS(t *testing.T) {
+	assert := require.New(t)
+
+	cfg := viper.New()
+	cfg.Set("rssLimit", 20)
+	cfg.Set("services", map[string]interface{}{
+		"rss": map[string]interface{}{
+			"title": "Site Feed",
+		},
+	})
+
+	config, err := DecodeConfig(cfg)
+	assert.NoError(err)
+	assert.Equal(20, config.RSS.Limit)
+	assert.Equal("Site Feed", config.RSS.Title)
+
+	cfg.Set("rss", map[string]interface{}{
+		"Limit": 5,
+		"title": "Language Feed",
+	})
+
+	config, err = DecodeConfig(cfg)
+	assert.NoError(err)
+	assert.Equal(5, config.RSS.Limit)
+	assert.Equal("Language Feed", config.RSS.Title)
+}
@@ -14,6 +14,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/spf13/cast"
 	jww "github.com/spf13/jwalterweatherman"
 )
@@ -23,6 +25,37 @@ type Sitemap struct {
 	ChangeFreq string
 	Priority   float64
 	Filename   string
+
+	// The layout used for lastmod. Defaults to the W3C Datetime format.
+	// Day and month names are translated to the site language.
+	DateFormat string
+
+	// The page kinds to include, e.g. ["page", "section", "home"].
+	// All kinds are included if not set.
+	Kinds []string
+
+	// Whether to leave out pages with a "noindex" robots directive in
+	// their front matter. Defaults to true.
+	ExcludeNoindex bool
+
+	// Whether to include draft, future and expired pages. These only
+	// leave out pages, as pages not built, see buildDrafts, buildFuture
+	// and buildExpired, are never listed. All default to true.
+	IncludeDrafts  bool
+	IncludeFuture  bool
+	IncludeExpired bool
+
+	// The page date to use for lastmod, one of "lastmod" (default), "date",
+	// "publishDate" or "git". The git author date requires enableGitInfo.
+	LastmodSource string
+
+	// Whether to minify the sitemap, even if the site isn't minified.
+	Minify bool
+
+	// The names of the output formats to list, each as a separate entry,
+	// e.g. ["html", "amp"]. Only the page's primary permalink is listed
+	// if not set.
+	OutputFormats []string
 }
 
 func DecodeSitemap(prototype Sitemap, input map[string]interface{}) Sitemap {
@@ -35,6 +68,28 @@ func DecodeSitemap(prototype Sitemap, input map[string]interface{}) Sitemap {
 			prototype.Priority = cast.ToFloat64(value)
 		case "filename":
 			prototype.Filename = cast.ToString(value)
+		case "dateformat":
+			prototype.DateFormat = cast.ToString(value)
+		case "kinds":
+			prototype.Kinds = cast.ToStringSlice(value)
+		case "excludenoindex":
+			prototype.ExcludeNoindex = cast.ToBool(value)
+		case "includedrafts":
+			prototype.IncludeDrafts = cast.ToBool(value)
+		case "includefuture":
+			prototype.IncludeFuture = cast.ToBool(value)
+		case "includeexpired":
+			prototype.IncludeExpired = cast.ToBool(value)
+		case "lastmodsource":
+			prototype.LastmodSource = cast.ToString(value)
+		case "minify":
+			prototype.Minify = cast.ToBool(value)
+		case "outputformats":
+			var formats []string
+			for _, name := range cast.ToStringSlice(value) {
+				formats = append(formats, strings.ToLower(name))
+			}
+			prototype.OutputFormats = formats
 		default:
 			jww.WARN.Printf("Unknown Sitemap field: %s\n", key)
 		}
@@ -49,6 +49,9 @@ type SiteConfig struct {
 
 	// Services contains config for services such as Google Analytics etc.
 	Services services.Config
+
+	// Robots contains the rules used by the embedded robots.txt template.
+	Robots config.Robots
 }
 
 func loadSiteConfig(cfg config.Provider) (scfg SiteConfig, err error) {
@@ -62,8 +65,14 @@ func loadSiteConfig(cfg config.Provider) (scfg SiteConfig, err error) {
 		return
 	}
 
+	robotsConfig, err := config.DecodeRobots(cfg)
+	if err != nil {
+		return
+	}
+
 	scfg.Privacy = privacyConfig
 	scfg.Services = servicesConfig
+	scfg.Robots = robotsConfig
 
 	return
 }
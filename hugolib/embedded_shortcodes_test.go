@@ -14,13 +14,19 @@
 package hugolib
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
 	"html/template"
+	"io/ioutil"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/spf13/cast"
+	jww "github.com/spf13/jwalterweatherman"
 
 	"path/filepath"
 
@@ -80,6 +86,56 @@ func doTestShortcodeCrossrefs(t *testing.T, relative bool) {
 	}
 }
 
+func TestShortcodeCrossrefsTrailingSlash(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/blog"`)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("target.md", `---
+title: Target
+---
+
+## Heading
+`, "source.md", `---
+title: Source
+---
+Default: {{< relref "target.md" >}}|
+NoSlash: {{< relref path="target.md" trailingSlash="false" >}}|
+NoSlashFragment: {{< relref path="target.md#heading" trailingSlash="false" >}}|
+Slash: {{< ref path="target.md" trailingSlash="true" >}}|
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/source/index.html",
+		"Default: /blog/target/|",
+		"NoSlash: /blog/target|",
+		"NoSlashFragment: /blog/target#heading|",
+		"Slash: http://example.com/blog/target/|",
+	)
+}
+
+func TestToggleTrailingSlash(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	for _, this := range []struct {
+		in       string
+		slash    bool
+		expected string
+	}{
+		{"/blog/post/", false, "/blog/post"},
+		{"/blog/post", true, "/blog/post/"},
+		{"/blog/post/?a=b#c", false, "/blog/post?a=b#c"},
+		{"/blog/post?a=b#c", true, "/blog/post/?a=b#c"},
+		{"/blog/post.html", true, "/blog/post.html"},
+		{"#frag", true, "#frag"},
+		{"/", false, "/"},
+	} {
+		assert.Equal(this.expected, toggleTrailingSlash(this.in, this.slash), this.in)
+	}
+}
+
 func TestShortcodeHighlight(t *testing.T) {
 	t.Parallel()
 
@@ -127,22 +183,42 @@ func TestShortcodeFigure(t *testing.T) {
 	}{
 		{
 			`{{< figure src="/img/hugo-logo.png" >}}`,
-			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\"/>.*?</figure>",
+			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\" loading=\"lazy\"/>.*?</figure>",
 		},
 		{
 			// set alt
 			`{{< figure src="/img/hugo-logo.png" alt="Hugo logo" >}}`,
-			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\".+?alt=\"Hugo logo\"/>.*?</figure>",
+			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\".+?alt=\"Hugo logo\" loading=\"lazy\"/>.*?</figure>",
 		},
 		// set title
 		{
 			`{{< figure src="/img/hugo-logo.png" title="Hugo logo" >}}`,
-			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\"/>.*?<figcaption>.*?<h4>Hugo logo</h4>.*?</figcaption>.*?</figure>",
+			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\" loading=\"lazy\"/>.*?<figcaption>.*?<h4>Hugo logo</h4>.*?</figcaption>.*?</figure>",
 		},
 		// set attr and attrlink
 		{
 			`{{< figure src="/img/hugo-logo.png" attr="Hugo logo" attrlink="/img/hugo-logo.png" >}}`,
-			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\"/>.*?<figcaption>.*?<p>.*?<a href=\"/img/hugo-logo.png\">.*?Hugo logo.*?</a>.*?</p>.*?</figcaption>.*?</figure>",
+			"(?s)<figure>.*?<img src=\"/img/hugo-logo.png\" loading=\"lazy\"/>.*?<figcaption>.*?<p>.*?<a href=\"/img/hugo-logo.png\">.*?Hugo logo.*?</a>.*?</p>.*?</figcaption>.*?</figure>",
+		},
+		// caption rendered as Markdown (default)
+		{
+			`{{< figure src="/img/hugo-logo.png" caption="See my_file_name *here*" >}}`,
+			"(?s)<img src=\"/img/hugo-logo.png\".+?alt=\"See my_file_name here\" loading=\"lazy\"/>.*?<p>See my_file_name <em>here</em>",
+		},
+		// multi-paragraph caption rendered as Markdown keeps its paragraphs
+		{
+			`{{< figure src="/img/hugo-logo.png" caption="See *here*.</p><p>Or *there*." >}}`,
+			"(?s)<figcaption>\\s*<p><p>See <em>here</em>.</p><p>Or <em>there</em>.</p>\\s*</p>\\s*</figcaption>",
+		},
+		// multi-paragraph caption rendered as inline Markdown is joined into one
+		{
+			`{{< figure src="/img/hugo-logo.png" caption="See *here*.</p><p>Or *there*." captionformat="inline" >}}`,
+			"(?s)<figcaption>\\s*<p>See <em>here</em>. Or <em>there</em>.</p>\\s*</figcaption>",
+		},
+		// caption rendered as plain text
+		{
+			`{{< figure src="/img/hugo-logo.png" caption="See my_file_*name*" captionformat="plain" >}}`,
+			"(?s)<img src=\"/img/hugo-logo.png\".+?alt=\"See my_file_\\*name\\*\" loading=\"lazy\"/>.*?<p>See my_file_\\*name\\*\\s*</p>",
 		},
 	} {
 
@@ -172,17 +248,27 @@ func TestShortcodeYoutube(t *testing.T) {
 	}{
 		{
 			`{{< youtube w7Ft2ymGmfc >}}`,
-			"(?s)\n<div style=\".*?\">.*?<iframe src=\"//www.youtube.com/embed/w7Ft2ymGmfc\" style=\".*?\" allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
+			"(?s)\n<div style=\".*?\">.*?<iframe src=\"https://www.youtube.com/embed/w7Ft2ymGmfc\" style=\".*?\" allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
 		},
 		// set class
 		{
 			`{{< youtube w7Ft2ymGmfc video>}}`,
-			"(?s)\n<div class=\"video\">.*?<iframe src=\"//www.youtube.com/embed/w7Ft2ymGmfc\" allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
+			"(?s)\n<div class=\"video\">.*?<iframe src=\"https://www.youtube.com/embed/w7Ft2ymGmfc\" allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
 		},
 		// set class and autoplay (using named params)
 		{
 			`{{< youtube id="w7Ft2ymGmfc" class="video" autoplay="true" >}}`,
-			"(?s)\n<div class=\"video\">.*?<iframe src=\"//www.youtube.com/embed/w7Ft2ymGmfc\\?autoplay=1\".*?allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>",
+			"(?s)\n<div class=\"video\">.*?<iframe src=\"https://www.youtube.com/embed/w7Ft2ymGmfc\\?autoplay=1\".*?allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>",
+		},
+		// set sandbox and referrerpolicy
+		{
+			`{{< youtube id="w7Ft2ymGmfc" sandbox="allow-scripts allow-same-origin" referrerpolicy="strict-origin-when-cross-origin" >}}`,
+			"(?s)\n<div style=\".*?\">.*?<iframe src=\"https://www.youtube.com/embed/w7Ft2ymGmfc\" style=\".*?\" allowfullscreen sandbox=\"allow-scripts allow-same-origin\" referrerpolicy=\"strict-origin-when-cross-origin\" title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
+		},
+		// protocol relative URL
+		{
+			`{{< youtube id="w7Ft2ymGmfc" protocolRelative="true" >}}`,
+			"(?s)\n<div style=\".*?\">.*?<iframe src=\"//www.youtube.com/embed/w7Ft2ymGmfc\" style=\".*?\" allowfullscreen title=\"YouTube Video\">.*?</iframe>.*?</div>\n",
 		},
 	} {
 		var (
@@ -211,16 +297,26 @@ func TestShortcodeVimeo(t *testing.T) {
 	}{
 		{
 			`{{< vimeo 146022717 >}}`,
-			"(?s)\n<div style=\".*?\">.*?<iframe src=\"//player.vimeo.com/video/146022717\" style=\".*?\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>\n",
+			"(?s)\n<div style=\".*?\">.*?<iframe src=\"https://player.vimeo.com/video/146022717\" style=\".*?\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>\n",
 		},
 		// set class
 		{
 			`{{< vimeo 146022717 video >}}`,
-			"(?s)\n<div class=\"video\">.*?<iframe src=\"//player.vimeo.com/video/146022717\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>\n",
+			"(?s)\n<div class=\"video\">.*?<iframe src=\"https://player.vimeo.com/video/146022717\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>\n",
 		},
 		// set class (using named params)
 		{
 			`{{< vimeo id="146022717" class="video" >}}`,
+			"(?s)^<div class=\"video\">.*?<iframe src=\"https://player.vimeo.com/video/146022717\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>",
+		},
+		// set sandbox and referrerpolicy
+		{
+			`{{< vimeo id="146022717" class="video" sandbox="allow-scripts allow-same-origin" referrerpolicy="no-referrer-when-downgrade" >}}`,
+			"(?s)^<div class=\"video\">.*?<iframe src=\"https://player.vimeo.com/video/146022717\" webkitallowfullscreen mozallowfullscreen allowfullscreen sandbox=\"allow-scripts allow-same-origin\" referrerpolicy=\"no-referrer-when-downgrade\">.*?</iframe>.*?</div>",
+		},
+		// protocol relative URL
+		{
+			`{{< vimeo id="146022717" class="video" protocolRelative="true" >}}`,
 			"(?s)^<div class=\"video\">.*?<iframe src=\"//player.vimeo.com/video/146022717\" webkitallowfullscreen mozallowfullscreen allowfullscreen>.*?</iframe>.*?</div>",
 		},
 	} {
@@ -256,6 +352,16 @@ func TestShortcodeGist(t *testing.T) {
 			`{{< gist spf13 7896402 "img.html" >}}`,
 			"(?s)^<script type=\"application/javascript\" src=\"https://gist.github.com/spf13/7896402.js\\?file=img.html\"></script>",
 		},
+		// named params
+		{
+			`{{< gist user="spf13" id="7896402" file="img.html" >}}`,
+			"(?s)^<script type=\"application/javascript\" src=\"https://gist.github.com/spf13/7896402.js\\?file=img.html\"></script>",
+		},
+		// protocol relative URL
+		{
+			`{{< gist user="spf13" id="7896402" protocolRelative="true" >}}`,
+			"(?s)^<script type=\"application/javascript\" src=\"//gist.github.com/spf13/7896402.js\"></script>",
+		},
 	} {
 		var (
 			cfg, fs = newTestCfg()
@@ -287,9 +393,9 @@ func TestShortcodeTweet(t *testing.T) {
 			`(?s)^<blockquote class="twitter-tweet"><p lang="en" dir="ltr">Hugo 0.15 will have 30%. faster render times thanks to this commit <a href="https://t.co/FfzhM8bNhT">https://t.co/FfzhM8bNhT</a>  <a href="https://twitter.com/hashtag/gohugo.src=hash">#gohugo</a> <a href="https://twitter.com/hashtag/golang.src=hash">#golang</a> <a href="https://t.co/ITbMNU2BUf">https://t.co/ITbMNU2BUf</a></p>&mdash; Steve Francia .@spf13. <a href="https://twitter.com/spf13/status/666616452582129664">November 17, 2015</a></blockquote>.*?<script async src="//platform.twitter.com/widgets.js" charset="utf-8"></script>`,
 		},
 	} {
-		// overload getJSON to return mock API response from Twitter
+		// overload getOEmbed to return mock API response from Twitter
 		tweetFuncMap := template.FuncMap{
-			"getJSON": func(urlParts ...string) interface{} {
+			"getOEmbed": func(urlParts ...string) interface{} {
 				var v interface{}
 				err := json.Unmarshal([]byte(this.resp), &v)
 				if err != nil {
@@ -342,9 +448,9 @@ func TestShortcodeInstagram(t *testing.T) {
 			`(?s)<blockquote class="instagram-media" data-instgrm-version="7" style=" background:#FFF; border:0; .*<script async defer src="//platform.instagram.com/en_US/embeds.js"></script>`,
 		},
 	} {
-		// overload getJSON to return mock API response from Instagram
+		// overload getOEmbed to return mock API response from Instagram
 		instagramFuncMap := template.FuncMap{
-			"getJSON": func(urlParts ...string) interface{} {
+			"getOEmbed": func(urlParts ...string) interface{} {
 				var v interface{}
 				err := json.Unmarshal([]byte(this.resp), &v)
 				if err != nil {
@@ -377,3 +483,1047 @@ title: Shorty
 
 	}
 }
+
+func TestShortcodeOEmbedFallback(t *testing.T) {
+	t.Parallel()
+
+	// Simulate a failed fetch.
+	withTemplate := func(templ tpl.TemplateHandler) error {
+		templ.(tpl.TemplateTestMocker).SetFuncs(template.FuncMap{
+			"getOEmbed": func(urlParts ...string) interface{} {
+				return nil
+			},
+		})
+		return nil
+	}
+
+	content := `---
+title: Social
+---
+{{< tweet 666616452582129664 >}}
+{{< instagram BMokmydjG-M >}}
+{{< vimeo_simple 146022717 >}}
+`
+
+	t.Run("Link", func(t *testing.T) {
+		cfg, fs := newTestCfg()
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilderFromDepsCfg(t, deps.DepsCfg{Fs: fs, Cfg: cfg, WithTemplate: withTemplate}).WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("social.md", content)
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/social/index.html",
+			`<a href="https://twitter.com/i/status/666616452582129664">View this tweet on Twitter</a>`,
+			`<a href="https://www.instagram.com/p/BMokmydjG-M/">View this post on Instagram</a>`,
+			`<a href="https://vimeo.com/146022717">Watch this video on Vimeo</a>`,
+		)
+		require.Equal(t, uint64(3), logger.WarnCounter.Count())
+	})
+
+	t.Run("FailOnError", func(t *testing.T) {
+		cfg, fs := newTestCfg()
+		cfg.Set("services", map[string]interface{}{
+			"oembed": map[string]interface{}{
+				"failOnError": true,
+			},
+		})
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilderFromDepsCfg(t, deps.DepsCfg{Fs: fs, Cfg: cfg, WithTemplate: withTemplate}).WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("social.md", content)
+
+		require.Error(t, b.BuildE(BuildCfg{}))
+		require.Contains(t, logger.Errors(), `The "tweet" shortcode failed to fetch https://twitter.com/i/status/666616452582129664`)
+	})
+}
+
+func TestShortcodeGitHub(t *testing.T) {
+	t.Parallel()
+
+	var fetched []string
+	withTemplate := func(templ tpl.TemplateHandler) error {
+		templ.(tpl.TemplateTestMocker).SetFuncs(template.FuncMap{
+			"getOEmbed": func(urlParts ...string) interface{} {
+				url := strings.Join(urlParts, "")
+				fetched = append(fetched, url)
+				if strings.Contains(url, "/gohugoio/missing") {
+					return nil
+				}
+				return map[string]interface{}{
+					"full_name":        "gohugoio/hugo",
+					"html_url":         "https://github.com/gohugoio/hugo",
+					"description":      "The world's fastest framework for building websites.",
+					"language":         "Go",
+					"stargazers_count": 42000,
+				}
+			},
+		})
+		return nil
+	}
+
+	cfg, fs := newTestCfg()
+	cfg.Set("services", map[string]interface{}{
+		"github": map[string]interface{}{
+			"token": "secret",
+		},
+	})
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilderFromDepsCfg(t, deps.DepsCfg{Fs: fs, Cfg: cfg, WithTemplate: withTemplate}).WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("repos.md", `---
+title: Repos
+---
+{{< github "gohugoio/hugo" >}}
+{{< github repo="gohugoio/missing" >}}
+`)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/repos/index.html",
+		`<a class="github-card-name" href="https://github.com/gohugoio/hugo">gohugoio/hugo</a>`,
+		`<p class="github-card-description">The world&#39;s fastest framework for building websites.</p>`,
+		`<span class="github-card-language">Go</span> <span class="github-card-stars" title="Stars">&#9733; 42000</span>`,
+		`<a href="https://github.com/gohugoio/missing">gohugoio/missing on GitHub</a>`,
+	)
+	require.Contains(t, fetched, "https://api.github.com/repos/gohugoio/hugo")
+	for _, url := range fetched {
+		// The token is sent by getOEmbed in a header, never in the URL.
+		require.NotContains(t, url, "secret")
+	}
+	require.Equal(t, uint64(1), logger.WarnCounter.Count())
+}
+
+func TestShortcodeGitHubInvalidRepo(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("repos.md", `---
+title: Repos
+---
+{{< github "hugo" >}}
+`)
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "github" shortcode requires a repository as owner/repo`)
+}
+
+func TestShortcodeFAQ(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("faq.md", `---
+title: FAQ
+---
+{{< faq >}}
+{{< question "What is <Hugo>?" >}}A **static** site generator.{{< /question >}}
+{{< question question="Is it fast?" >}}Yes, "very".{{< /question >}}
+{{< /faq >}}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/faq/index.html",
+		`<summary>What is &lt;Hugo&gt;?</summary>`,
+		`<div class="faq-answer">A <strong>static</strong> site generator.</div>`,
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"FAQPage","mainEntity":[{"@type":"Question","acceptedAnswer":{"@type":"Answer","text":"A \u003cstrong\u003estatic\u003c/strong\u003e site generator."},"name":"What is \u003cHugo\u003e?"},{"@type":"Question","acceptedAnswer":{"@type":"Answer","text":"Yes, \u0026ldquo;very\u0026rdquo;."},"name":"Is it fast?"}]}</script>`,
+	)
+}
+
+func TestShortcodeQuote(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("quotes.md", `---
+title: Quotes
+---
+{{< quote author="Ada Lovelace" source="Notes" url="https://example.org/notes" >}}The engine **weaves** patterns.{{< /quote >}}
+
+{{< quote author="Ada Lovelace" schema="true" class="pull" >}}Imagination is the discovering faculty.{{< /quote >}}
+
+{{< quote source="Notes" url="https://example.org/notes" schema="true" >}}Sourced.{{< /quote >}}
+
+{{< quote url="https://example.org/anonymous" >}}Anonymous.{{< /quote >}}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/quotes/index.html",
+		`<blockquote class="quote" cite="https://example.org/notes">
+  <div class="quote-text">The engine <strong>weaves</strong> patterns.</div>
+  <footer><span class="quote-author">Ada Lovelace</span>, <cite><a href="https://example.org/notes">Notes</a></cite></footer>
+</blockquote>`,
+		`<blockquote class="quote pull" itemscope itemtype="https://schema.org/Quotation">
+  <div class="quote-text" itemprop="text">Imagination is the discovering faculty.</div>
+  <footer><span class="quote-author" itemprop="creator" itemscope itemtype="https://schema.org/Person"><span itemprop="name">Ada Lovelace</span></span></footer>
+</blockquote>`,
+		`<footer><cite><a href="https://example.org/notes" itemprop="isBasedOn">Notes</a></cite></footer>`,
+		`<blockquote class="quote" cite="https://example.org/anonymous">
+  <div class="quote-text">Anonymous.</div>
+</blockquote>`,
+	)
+}
+
+func TestShortcodeTable(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Tables
+---
+{{< table "people.csv" >}}
+
+{{< table src="semicolons.csv" delimiter=";" columns="age, name" caption="Ages" sortable="true" class="wide" >}}
+
+{{< table src="people.json" sortable="true" >}}
+`)
+	b.WithSourceFile(
+		"content/bundle/people.csv", "name,age\nJane,30\nJohn,<25>\n",
+		"content/bundle/semicolons.csv", "name;age\nJane;30\n",
+		"content/bundle/people.json", `[{"name": "Jane", "age": 30, "tags": ["a", "b"]}, {"name": "John", "age": 0, "tags": null}]`,
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<table class="table">
+  <thead>
+    <tr>
+      <th>name</th>
+      <th>age</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td>Jane</td>
+      <td>30</td>
+    </tr>
+    <tr>
+      <td>John</td>
+      <td>&lt;25&gt;</td>
+    </tr>
+  </tbody>
+</table>`,
+		`<table class="table wide sortable">
+  <caption>Ages</caption>
+  <thead>
+    <tr>
+      <th>age</th>
+      <th>name</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td>30</td>
+      <td>Jane</td>
+    </tr>
+  </tbody>
+</table>
+<script>`,
+		`<tr>
+      <th>age</th>
+      <th>name</th>
+      <th>tags</th>
+    </tr>`,
+		`<tr>
+      <td>30</td>
+      <td>Jane</td>
+      <td>[a b]</td>
+    </tr>
+    <tr>
+      <td>0</td>
+      <td>John</td>
+      <td></td>
+    </tr>`,
+	)
+
+	// The sorting script is only included once.
+	require.Equal(t, 1, strings.Count(b.FileContent("public/bundle/index.html"), "<script>"))
+}
+
+func TestShortcodeTableErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shortcode string
+		expected  string
+	}{
+		{`{{< table >}}`, `The "table" shortcode requires a src: "content/bundle/index.md:4:1"`},
+		{`{{< table "missing.csv" >}}`, `could not find the resource "missing.csv"`},
+		{`{{< table "notes.txt" >}}`, `can only read CSV or JSON, got "notes.txt"`},
+		{`{{< table "empty.json" >}}`, `found no rows in "empty.json"`},
+		{`{{< table "object.json" >}}`, `found no rows in "object.json"`},
+		{`{{< table src="people.csv" columns="name,email" >}}`, `got the unknown column "email" for "people.csv"`},
+	} {
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("bundle/index.md", "---\ntitle: Table\n---\n"+test.shortcode+"\n")
+		b.WithSourceFile(
+			"content/bundle/people.csv", "name,age\nJane,30\n",
+			"content/bundle/notes.txt", "notes",
+			"content/bundle/empty.json", "[]",
+			"content/bundle/object.json", `{"name": "Jane"}`,
+		)
+
+		require.Error(t, b.BuildE(BuildCfg{}), test.shortcode)
+		require.Contains(t, logger.Errors(), test.expected, test.shortcode)
+	}
+}
+
+func TestShortcodeVideo(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Video
+---
+{{< video "clip.mp4" >}}
+
+{{< video src="clip.webm" autoplay="true" muted="true" loop="true" width="640" loading="lazy" poster="https://example.org/poster.jpg" class="big" >}}
+
+{{< video src="/videos/remote.ogv" preload="auto" >}}
+`)
+	b.WithSourceFile(
+		"content/bundle/clip.mp4", "mp4",
+		"content/bundle/clip.webm", "webm",
+	)
+	b.WithSunset("content/bundle/clip-thumbnail.jpg")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<video controls preload="metadata" poster="/bundle/clip-thumbnail.jpg" style="max-width: 100%; height: auto;">
+  <source src="/bundle/clip.mp4" type="video/mp4">
+  <source src="/bundle/clip.webm" type="video/webm">
+</video>`,
+		`<video controls preload="none" poster="https://example.org/poster.jpg" width="640" autoplay playsinline muted loop class="big">
+  <source src="/bundle/clip.webm" type="video/webm">
+  <source src="/bundle/clip.mp4" type="video/mp4">
+</video>`,
+		`<video controls preload="auto" poster="/bundle/clip-thumbnail.jpg" style="max-width: 100%; height: auto;">
+  <source src="/videos/remote.ogv" type="video/ogg">
+</video>`,
+	)
+}
+
+func TestShortcodeVideoMissingResource(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Video
+---
+{{< video "missing.mp4" >}}
+`)
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "video" shortcode could not find the resource "missing.mp4": "content/bundle/index.md:4:1"`)
+}
+
+func TestShortcodePDF(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: PDF
+resources:
+- src: manual.pdf
+  title: The Manual
+---
+{{< pdf "manual.pdf" >}}
+
+{{< pdf src="manual.pdf" page="3" height="400" class="wide" title="Chapter 3" >}}
+
+{{< pdf src="https://example.org/docs/guide.pdf" height="80vh" >}}
+`)
+	b.WithSourceFile("content/bundle/manual.pdf", "pdf")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<div class="pdf">
+  <iframe src="/bundle/manual.pdf" style="width: 100%; height: 600px; border: 0;" loading="lazy" title="The Manual"></iframe>
+  <p><a href="/bundle/manual.pdf" download>Download The Manual</a></p>
+</div>`,
+		`<div class="pdf wide">
+  <iframe src="/bundle/manual.pdf#page=3" style="width: 100%; height: 400px; border: 0;" loading="lazy" title="Chapter 3"></iframe>
+  <p><a href="/bundle/manual.pdf" download>Download Chapter 3</a></p>
+</div>`,
+		`<div class="pdf">
+  <iframe src="https://example.org/docs/guide.pdf" style="width: 100%; height: 80vh; border: 0;" loading="lazy" title="guide.pdf"></iframe>
+  <p><a href="https://example.org/docs/guide.pdf" download>Download guide.pdf</a></p>
+</div>`,
+	)
+}
+
+func TestShortcodePDFMissingResource(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: PDF
+---
+{{< pdf "missing.pdf" >}}
+`)
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "pdf" shortcode could not find the resource "missing.pdf": "content/bundle/index.md:4:1"`)
+}
+
+func TestShortcodeAudio(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Audio
+---
+{{< audio "episode.ogg" >}}
+
+{{< audio src="https://example.org/remote.mp3" preload="none" title="Episode 1" caption="With *guests*" >}}
+`)
+	b.WithSourceFile(
+		"content/bundle/episode.mp3", "mp3",
+		"content/bundle/episode.ogg", "ogg",
+		"content/bundle/episode.txt", "notes",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<audio controls preload="metadata">
+  <source src="/bundle/episode.ogg" type="audio/ogg">
+  <source src="/bundle/episode.mp3" type="audio/mpeg">
+</audio>`,
+		`<figure class="audio">
+<audio controls preload="none" title="Episode 1">
+  <source src="https://example.org/remote.mp3" type="audio/mpeg">
+</audio>
+  <figcaption><strong>Episode 1</strong> With <em>guests</em></figcaption>
+</figure>`,
+	)
+}
+
+func TestShortcodeAudioMissingResource(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Audio
+---
+{{< audio src="missing.mp3" >}}
+`)
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "audio" shortcode could not find the resource "missing.mp3": "content/bundle/index.md:4:1"`)
+}
+
+func TestShortcodeTwitterTimeline(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[privacy.twitter]
+enableDNT = true
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("p1.md", `---
+title: Timeline
+---
+{{< twitter_timeline "@gohugoio" >}}
+
+{{< twitter_timeline user="spf13" height="600" theme="dark" limit="5" >}}
+
+{{< twitter_timeline collection="https://twitter.com/TwitterDev/timelines/539487832448843776" >}}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`<a class="twitter-timeline" href="https://twitter.com/gohugoio" data-dnt="true">Tweets by gohugoio</a>
+<script async src="https://platform.twitter.com/widgets.js" charset="utf-8"></script>`,
+		`<a class="twitter-timeline" href="https://twitter.com/spf13" data-height="600" data-theme="dark" data-tweet-limit="5" data-dnt="true">Tweets by spf13</a>`,
+		`<a class="twitter-timeline" href="https://twitter.com/TwitterDev/timelines/539487832448843776" data-dnt="true">A Twitter collection</a>`,
+	)
+	require.Equal(t, 1, strings.Count(b.FileContent("public/p1/index.html"), "widgets.js"))
+}
+
+func TestShortcodeTwitterTimelineErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		config    string
+		shortcode string
+		expect    string
+	}{
+		{"Simple", "[privacy.twitter]\nsimple = true", `{{< twitter_timeline "gohugoio" >}}`, `The "twitter_timeline" shortcode requires Twitter's widgets.js and does not support privacy.twitter.simple`},
+		{"No user", "", `{{< twitter_timeline height="600" >}}`, `The "twitter_timeline" shortcode requires a user or a collection`},
+		{"Theme", "", `{{< twitter_timeline user="gohugoio" theme="blue" >}}`, `The "twitter_timeline" shortcode theme must be light or dark, got "blue"`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+			b := newTestSitesBuilder(t).WithLogger(logger).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+			b.WithContent("p1.md", "---\ntitle: Timeline\n---\n"+test.shortcode+"\n")
+
+			require.Error(t, b.BuildE(BuildCfg{}))
+			require.Contains(t, logger.Errors(), test.expect)
+		})
+	}
+}
+
+func TestShortcodeTwitterTimelineDisabled(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[privacy.twitter]
+disable = true
+simple = true
+`)
+	b.WithTemplatesAdded("_default/single.html", `Timeline:{{ .Content }}`)
+	b.WithContent("p1.md", "---\ntitle: Timeline\n---\n{{< twitter_timeline \"gohugoio\" >}}\n")
+	b.Build(BuildCfg{})
+
+	require.NotContains(t, b.FileContent("public/p1/index.html"), "twitter")
+}
+
+func TestShortcodeImageLoading(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.images]
+loading = "eager"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("p1.md", `---
+title: Images
+---
+{{< figure src="/a.png" >}}
+
+{{< figure src="/b.png" loading="lazy" >}}
+`)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`<img src="/a.png" loading="eager"/>`,
+		`<img src="/b.png" loading="lazy"/>`,
+	)
+}
+
+func TestShortcodeImageLoadingInvalid(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("p1.md", "---\ntitle: Images\n---\n{{< figure src=\"/a.png\" loading=\"later\" >}}\n")
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "figure" shortcode loading must be lazy or eager, got "later"`)
+}
+
+func TestShortcodeFigureSrcset(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config   string
+		expected []string
+	}{
+		{"", []string{"480w", "768w"}},
+		{"[params.images]\nallowUpscale = true", []string{"480w", "768w", "1200w"}},
+		{"[params.images]\nsrcsetWidths = [600, 300]", []string{"300w", "600w"}},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("bundle/index.md", `---
+title: Bundle
+---
+{{< figure src="sunset.jpg" sizes="50vw" >}}
+
+{{< figure src="/remote.jpg" >}}
+`)
+		b.WithSunset("content/bundle/sunset.jpg")
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/bundle/index.html")
+		srcset := regexp.MustCompile(`srcset="([^"]*)"`).FindStringSubmatch(content)
+		require.NotNil(t, srcset, content)
+
+		var widths []string
+		for _, candidate := range strings.Split(srcset[1], ", ") {
+			require.True(t, strings.HasPrefix(candidate, "/bundle/sunset_hu"), candidate)
+			widths = append(widths, candidate[strings.LastIndex(candidate, " ")+1:])
+		}
+		require.Equal(t, test.expected, widths, test.config)
+		require.Contains(t, content, `sizes="50vw"`)
+		require.Contains(t, content, `<img src="/remote.jpg" loading="lazy"/>`)
+	}
+}
+
+func TestShortcodeFigurePlaceholder(t *testing.T) {
+	t.Parallel()
+
+	placeholderRe := regexp.MustCompile(`style="background-image:url\(data:([^;]*);base64,([^)]*)\);background-size:cover" onload="this.style.backgroundImage='none'"`)
+
+	for _, test := range []struct {
+		config    string
+		shortcode string
+		mediaType string
+	}{
+		{"", `{{< figure src="sunset.jpg" >}}`, ""},
+		{"", `{{< figure src="sunset.jpg" placeholder="blur" >}}`, "image/jpeg"},
+		{"", `{{< figure src="sunset.jpg" placeholder="svg" >}}`, "image/svg+xml"},
+		{"[params.images]\nplaceholder = \"blur\"", `{{< figure src="sunset.jpg" >}}`, "image/jpeg"},
+		{"[params.images]\nplaceholder = \"blur\"", `{{< figure src="sunset.jpg" placeholder="none" >}}`, ""},
+		{"[params.images]\nplaceholder = \"blur\"", `{{< figure src="/remote.jpg" >}}`, ""},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("bundle/index.md", "---\ntitle: Bundle\n---\n"+test.shortcode+"\n")
+		b.WithSunset("content/bundle/sunset.jpg")
+		b.Build(BuildCfg{})
+
+		content := html.UnescapeString(b.FileContent("public/bundle/index.html"))
+		placeholder := placeholderRe.FindStringSubmatch(content)
+		if test.mediaType == "" {
+			require.Nil(t, placeholder, content)
+			require.NotContains(t, content, "base64")
+			continue
+		}
+		require.NotNil(t, placeholder, content)
+		require.Equal(t, test.mediaType, placeholder[1])
+
+		data, err := base64.StdEncoding.DecodeString(placeholder[2])
+		require.NoError(t, err)
+		if test.mediaType == "image/svg+xml" {
+			require.Contains(t, string(data), "<feGaussianBlur")
+			require.Contains(t, string(data), "data:image/jpeg;base64,")
+		} else {
+			// A JPEG, a lot smaller than the original.
+			require.True(t, strings.HasPrefix(string(data), "\xff\xd8"))
+			require.True(t, len(data) < 2000, len(data))
+		}
+	}
+}
+
+func TestShortcodeFigurePlaceholderErrors(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("p1.md", "---\ntitle: Figure\n---\n{{< figure src=\"/remote.jpg\" placeholder=\"pixelate\" >}}\n")
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `The "figure" shortcode placeholder must be none, blur or svg, got "pixelate": "content/p1.md:4:1"`)
+}
+
+func TestShortcodeAsciinema(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Asciinema
+---
+{{< asciinema 113463 >}}
+
+{{< asciinema id="113464" theme="monokai" speed="2" autoplay="true" >}}
+
+{{< asciinema id="113465" loading="lazy" theme="solarized-dark" autoplay="true" >}}
+
+{{< asciinema "demo.cast" >}}
+
+{{< asciinema src="demo.cast" theme="tango" speed="1.5" autoplay="true" >}}
+`)
+	b.WithSourceFile("content/bundle/demo.cast", `{"version": 2}`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<script id="asciicast-113463" src="https://asciinema.org/a/113463.js" async></script>`,
+		`<script id="asciicast-113464" src="https://asciinema.org/a/113464.js" data-theme="monokai" data-speed="2" data-autoplay="true" async></script>`,
+		`<iframe src="https://asciinema.org/a/113465/iframe?theme=solarized-dark&amp;autoplay=1" loading="lazy" style="width: 100%; border: 0;" title="asciicast 113465" allowfullscreen></iframe>`,
+		`<asciinema-player src="/bundle/demo.cast"></asciinema-player>`,
+		`<asciinema-player src="/bundle/demo.cast" theme="tango" speed="1.5" autoplay></asciinema-player>`,
+	)
+}
+
+func TestShortcodeAsciinemaErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shortcode string
+		expect    string
+	}{
+		{`{{< asciinema theme="monokai" >}}`, `The "asciinema" shortcode requires a recording id or a .cast file: "content/p1.md:4:1"`},
+		{`{{< asciinema "missing.cast" >}}`, `The "asciinema" shortcode could not find the resource "missing.cast"`},
+	} {
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("p1.md", "---\ntitle: Asciinema\n---\n"+test.shortcode+"\n")
+
+		require.Error(t, b.BuildE(BuildCfg{}))
+		require.Contains(t, logger.Errors(), test.expect)
+	}
+}
+
+func TestShortcodePlaygrounds(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("p1.md", `---
+title: Playgrounds
+---
+{{< jsfiddle "zalun/NmudS" >}}
+
+{{< jsfiddle id="https://jsfiddle.net/zalun/NmudS/embedded/result/" tabs="result,js" theme="dark" height="400" class="wide" >}}
+
+{{< codesandbox "new" >}}
+
+{{< codesandbox id="https://codesandbox.io/s/q4qnn7z3j2?file=/src/App.js" view="split" file="/src/App.js" height="50vh" title="Counter" >}}
+
+{{< stackblitz "angular-ivy" >}}
+
+{{< stackblitz id="https://stackblitz.com/edit/angular-ivy/" view="preview" file="src/main.ts" >}}
+`)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html",
+		`<div class="playground playground-jsfiddle">
+  <iframe src="https://jsfiddle.net/zalun/NmudS/embedded/js,html,css,result/" style="width: 100%; height: 300px; border: 0;" loading="lazy" title="JSFiddle zalun/NmudS" allowfullscreen></iframe>
+</div>`,
+		`<div class="playground playground-jsfiddle wide">
+  <iframe src="https://jsfiddle.net/zalun/NmudS/embedded/result,js/dark/" style="width: 100%; height: 400px; border: 0;" loading="lazy" title="JSFiddle zalun/NmudS" allowfullscreen></iframe>`,
+		`<iframe src="https://codesandbox.io/embed/new" style="width: 100%; height: 500px; border: 0;" loading="lazy" title="CodeSandbox new" allowfullscreen></iframe>`,
+		`<iframe src="https://codesandbox.io/embed/q4qnn7z3j2?view=split&amp;module=%2Fsrc%2FApp.js" style="width: 100%; height: 50vh; border: 0;" loading="lazy" title="Counter" allowfullscreen></iframe>`,
+		`<iframe src="https://stackblitz.com/edit/angular-ivy?embed=1" style="width: 100%; height: 500px; border: 0;" loading="lazy" title="StackBlitz angular-ivy" allowfullscreen></iframe>`,
+		`<iframe src="https://stackblitz.com/edit/angular-ivy?embed=1&amp;view=preview&amp;file=src%2Fmain.ts"`,
+	)
+}
+
+func TestShortcodePlaygroundsErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shortcode string
+		expect    string
+	}{
+		{`{{< jsfiddle height="300" >}}`, `The "jsfiddle" shortcode requires a fiddle id or URL: "content/p1.md:4:1"`},
+		{`{{< jsfiddle "zalun/<script>" >}}`, `The "jsfiddle" shortcode got an invalid fiddle id "zalun/<script>": "content/p1.md:4:1"`},
+		{`{{< codesandbox >}}`, `The "codesandbox" shortcode requires a sandbox id or URL: "content/p1.md:4:1"`},
+		{`{{< codesandbox id="new" view="both" >}}`, `The "codesandbox" shortcode view must be editor, split or preview, got "both": "content/p1.md:4:1"`},
+		{`{{< stackblitz id="" >}}`, `The "stackblitz" shortcode requires a project id or URL: "content/p1.md:4:1"`},
+		{`{{< stackblitz "a/b" >}}`, `The "stackblitz" shortcode got an invalid project id "a/b": "content/p1.md:4:1"`},
+	} {
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("p1.md", "---\ntitle: Playground\n---\n"+test.shortcode+"\n")
+
+		require.Error(t, b.BuildE(BuildCfg{}))
+		require.Contains(t, logger.Errors(), test.expect)
+	}
+}
+
+func TestShortcodeTweetURL(t *testing.T) {
+	t.Parallel()
+
+	for _, simple := range []bool{false, true} {
+		var fetched []string
+		withTemplate := func(templ tpl.TemplateHandler) error {
+			templ.(tpl.TemplateTestMocker).SetFuncs(template.FuncMap{
+				"getOEmbed": func(urlParts ...string) interface{} {
+					fetched = append(fetched, strings.Join(urlParts, ""))
+					return map[string]interface{}{"html": "<blockquote>tweet</blockquote>"}
+				},
+			})
+			return nil
+		}
+
+		cfg, fs := newTestCfg()
+		cfg.Set("privacy", map[string]interface{}{
+			"twitter": map[string]interface{}{
+				"simple": simple,
+			},
+		})
+		b := newTestSitesBuilderFromDepsCfg(t, deps.DepsCfg{Fs: fs, Cfg: cfg, WithTemplate: withTemplate})
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("tweets.md", `---
+title: Tweets
+---
+{{< tweet 666616452582129664 >}}
+{{< tweet "https://twitter.com/spf13/status/666616452582129665" >}}
+{{< tweet "https://x.com/spf13/status/666616452582129666?s=20&t=abc" >}}
+{{< tweet "https://mobile.twitter.com/spf13/status/666616452582129667/" >}}
+{{< tweet "twitter.com/i/web/status/666616452582129668" >}}
+`)
+		b.Build(BuildCfg{})
+
+		all := strings.Join(fetched, "\n")
+		for i := 4; i <= 8; i++ {
+			require.Contains(t, all, fmt.Sprintf("id=66661645258212966%d", i))
+		}
+		require.Equal(t, simple, strings.Contains(all, "omit_script=true"))
+	}
+}
+
+func TestShortcodeTweetURLInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{
+		`{{< tweet "https://twitter.com/spf13" >}}`,
+		`{{< tweet "https://example.com/spf13/status/666616452582129664" >}}`,
+		`{{< tweet >}}`,
+	} {
+		cfg, fs := newTestCfg()
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilderFromDepsCfg(t, deps.DepsCfg{Fs: fs, Cfg: cfg}).WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("tweets.md", "---\ntitle: Tweets\n---\n"+in+"\n")
+
+		require.Error(t, b.BuildE(BuildCfg{}), in)
+		require.Contains(t, logger.Errors(), `The "tweet" shortcode requires a tweet ID or URL`, in)
+	}
+}
+
+func TestShortcodeGoogleMap(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		config  string
+		expect  []string
+		missing string
+	}{
+		{"Default", "", []string{
+			`<iframe src="https://maps.google.com/maps?q=Eiffel&#43;Tower%2C&#43;Paris&amp;z=14&amp;output=embed" style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" loading="lazy" referrerpolicy="no-referrer-when-downgrade" allowfullscreen title="Google Map"></iframe>`,
+			`<div class="map">
+  <iframe src="https://maps.google.com/maps?q=48.8584%2C2.2945&amp;z=16&amp;output=embed" loading="lazy"`,
+		}, "staticmap"},
+		{"API key", "[services.googleMaps]\napiKey = \"KEY\"", []string{
+			`<iframe src="https://www.google.com/maps/embed/v1/place?key=KEY&amp;q=Eiffel&#43;Tower%2C&#43;Paris&amp;zoom=14"`,
+		}, "staticmap"},
+		{"Simple", "[services.googleMaps]\napiKey = \"KEY\"\n[privacy.googleMaps]\nsimple = true", []string{
+			`<div class="s_map_simple __h_video">`,
+			`<a href="https://www.google.com/maps/search/?api=1&amp;query=Eiffel%20Tower%2c%20Paris" data-h-map="https://www.google.com/maps/embed/v1/place?key=KEY&amp;q=Eiffel&#43;Tower%2C&#43;Paris&amp;zoom=14" target="_blank">`,
+			`<img src="https://maps.googleapis.com/maps/api/staticmap?center=Eiffel&#43;Tower%2C&#43;Paris&amp;zoom=14&amp;size=640x360&amp;markers=Eiffel&#43;Tower%2C&#43;Paris&amp;key=KEY" alt="Map of Eiffel Tower, Paris" loading="lazy">`,
+		}, "<iframe"},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+			b.WithContent("p1.md", `---
+title: Map
+---
+{{< googlemap "Eiffel Tower, Paris" >}}
+
+{{< googlemap lat="48.8584" lon="2.2945" zoom="16" class="map" >}}
+`)
+			b.Build(BuildCfg{})
+
+			content := b.FileContent("public/p1/index.html")
+			b.AssertFileContent("public/p1/index.html", test.expect...)
+			require.NotContains(t, content, test.missing)
+		})
+	}
+}
+
+func TestShortcodeGoogleMapErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		config    string
+		shortcode string
+		expect    string
+	}{
+		{"No location", "", `{{< googlemap zoom="10" >}}`, `The "googlemap" shortcode requires coordinates or a query`},
+		{"Latitude only", "", `{{< googlemap lat="48.8584" >}}`, `The "googlemap" shortcode requires coordinates or a query`},
+		{"Simple without key", "[privacy.googleMaps]\nsimple = true", `{{< googlemap "Paris" >}}`, `The "googlemap" shortcode requires services.googleMaps.apiKey for the Static Maps API`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+			b := newTestSitesBuilder(t).WithLogger(logger).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+			b.WithContent("p1.md", "---\ntitle: Map\n---\n"+test.shortcode+"\n")
+
+			require.Error(t, b.BuildE(BuildCfg{}))
+			require.Contains(t, logger.Errors(), test.expect)
+		})
+	}
+}
+
+func TestShortcodeGoogleMapDisabled(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[privacy.googleMaps]
+disable = true
+simple = true
+`)
+	b.WithTemplatesAdded("_default/single.html", `Map:{{ .Content }}`)
+	b.WithContent("p1.md", "---\ntitle: Map\n---\n{{< googlemap \"Paris\" >}}\n")
+	b.Build(BuildCfg{})
+
+	require.NotContains(t, b.FileContent("public/p1/index.html"), "google")
+}
+
+func TestShortcodePicture(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Picture
+---
+{{< picture src="sunset.jpg" alt="A sunset" process="resize 600x" class="art" >}}
+{{< source src="sunset.jpg" media="(max-width: 480px)" process="fill 300x400 Center" >}}
+{{< source src="/images/wide.webp" media="(min-width: 1200px)" type="image/webp" >}}
+{{< /picture >}}
+
+{{< picture src="https://example.org/fallback.jpg" loading="eager" />}}
+`)
+	b.WithSunset("content/bundle/sunset.jpg")
+
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/bundle/index.html")
+	require.Regexp(t, regexp.MustCompile(`<picture class="art">
+  <source media="\(max-width: 480px\)" srcset="/bundle/sunset_hu[0-9a-f]+_[0-9]+_300x400_fill_q75_box_center.jpg" width="300" height="400">
+  <source media="\(min-width: 1200px\)" type="image/webp" srcset="/images/wide.webp">
+  <img src="/bundle/sunset_hu[0-9a-f]+_[0-9]+_600x0_resize_q75_box.jpg" alt="A sunset" width="600" height="375" loading="lazy">
+</picture>`), content)
+	b.AssertFileContent("public/bundle/index.html", `<picture>
+  <img src="https://example.org/fallback.jpg" alt="" loading="eager">
+</picture>`)
+}
+
+func TestShortcodePictureErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shortcode string
+		expect    string
+	}{
+		{`{{< picture alt="x" >}}{{< /picture >}}`, `The "picture" shortcode requires a src for the fallback image`},
+		{`{{< source src="a.jpg" media="(max-width: 480px)" >}}`, `The "source" shortcode must be used inside a picture shortcode`},
+		{`{{< picture "a.jpg" >}}{{< source media="(max-width: 480px)" >}}{{< /picture >}}`, `The "source" shortcode requires a src`},
+		{`{{< picture src="sunset.jpg" process="crop 100x100" />}}`, `The "picture" shortcode process must start with fill, fit or resize, got "crop 100x100"`},
+		{`{{< picture src="missing.jpg" process="fill 100x100" />}}`, `The "picture" shortcode can only process page resources, got "missing.jpg"`},
+	} {
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("bundle/index.md", "---\ntitle: Picture\n---\n"+test.shortcode+"\n")
+		b.WithSunset("content/bundle/sunset.jpg")
+
+		require.Error(t, b.BuildE(BuildCfg{}))
+		require.Contains(t, logger.Errors(), test.expect)
+	}
+}
+
+func TestShortcodeAttachments(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+	b.WithContent("bundle/index.md", `---
+title: Attachments
+resources:
+- src: files/guide.pdf
+  title: The Guide
+---
+{{< attachments "*.pdf, files/*" >}}
+
+{{< attachments include="**" exclude="*.txt" sort="size" order="desc" >}}
+
+{{< attachments "*.doc" >}}
+`)
+	b.WithContent("bundle/other.md", "---\ntitle: Other\n---\n")
+	b.WithSourceFile(
+		"content/bundle/b.pdf", strings.Repeat("b", 2048),
+		"content/bundle/files/guide.pdf", strings.Repeat("g", 1572864),
+		"content/bundle/files/data.zip", "zip",
+		"content/bundle/notes.txt", "notes",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html", `<ul class="attachments">
+  <li><a href="/bundle/b.pdf" download="b.pdf">b.pdf</a> <span class="attachment-size">2.0 KB</span></li>
+  <li><a href="/bundle/files/data.zip" download="data.zip">data.zip</a> <span class="attachment-size">3 B</span></li>
+  <li><a href="/bundle/files/guide.pdf" download="guide.pdf">The Guide</a> <span class="attachment-size">1.5 MB</span></li>
+</ul>`, `<ul class="attachments">
+  <li><a href="/bundle/files/guide.pdf" download="guide.pdf">The Guide</a> <span class="attachment-size">1.5 MB</span></li>
+  <li><a href="/bundle/b.pdf" download="b.pdf">b.pdf</a> <span class="attachment-size">2.0 KB</span></li>
+  <li><a href="/bundle/files/data.zip" download="data.zip">data.zip</a> <span class="attachment-size">3 B</span></li>
+</ul>`)
+
+	content := b.FileContent("public/bundle/index.html")
+	require.Equal(t, 2, strings.Count(content, `<ul class="attachments">`))
+	require.NotContains(t, content, "notes.txt")
+	require.NotContains(t, content, "other")
+}
+
+func TestShortcodeAttachmentsErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shortcode string
+		expect    string
+	}{
+		{`{{< attachments sort="date" >}}`, `The "attachments" shortcode sort must be name or size, got "date"`},
+		{`{{< attachments order="up" >}}`, `The "attachments" shortcode order must be asc or desc, got "up"`},
+	} {
+		logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+		b := newTestSitesBuilder(t).WithSimpleConfigFile().WithLogger(logger)
+		b.WithTemplatesAdded("_default/single.html", `{{ .Content }}`)
+		b.WithContent("p1.md", "---\ntitle: Attachments\n---\n"+test.shortcode+"\n")
+
+		require.Error(t, b.BuildE(BuildCfg{}))
+		require.Contains(t, logger.Errors(), test.expect)
+	}
+}
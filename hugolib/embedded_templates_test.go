@@ -14,8 +14,14 @@
 package hugolib
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/gohugoio/hugo/common/loggers"
+	jww "github.com/spf13/jwalterweatherman"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,3 +62,1316 @@ Disqus:
 	// Disqus
 	b.AssertFileContent("public/index.html", "\"disqus_shortname\" + '.disqus.com/embed.js';")
 }
+
+func TestEmbeddedTemplatesOpenGraphImageType(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithSimpleConfigFile().WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent("bundle/index.md", `---
+title: Bundle
+images: ["cover.png", "/images/photo.JPG", "/images/unknown.tiff"]
+---
+`)
+	b.WithSunset("content/bundle/cover.png")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<meta property="og:image" content="http://example.com/cover.png" />
+<meta property="og:image:width" content="900" />
+<meta property="og:image:height" content="562" />
+<meta property="og:image:type" content="image/png" />`,
+		`<meta property="og:image" content="http://example.com/images/photo.JPG" />
+<meta property="og:image:type" content="image/jpeg" />`,
+		`<meta property="og:image" content="http://example.com/images/unknown.tiff" />
+
+`,
+	)
+}
+
+func TestEmbeddedTemplatesSchemaCollection(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	logger := loggers.NewLogger(jww.LevelWarn, jww.LevelError, &warnings, ioutil.Discard, false)
+
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+paginate = 3
+`)
+	b.WithTemplatesAdded(
+		"_default/list.html", `{{ $pager := .Paginate .Pages 2 }}{{ template "_internal/schema_collection.html" (dict "page" . "paginator" $pager) }}`,
+		"_default/single.html", `Single:{{ template "_internal/schema_collection.html" . }}`,
+	)
+	b.WithContent(
+		"blog/_index.md", "---\ntitle: Blog\n---",
+		"blog/p1.md", "---\ntitle: P1\nweight: 1\n---",
+		"blog/p2.md", "---\ntitle: P2\nweight: 2\n---",
+		"blog/p3.md", "---\ntitle: P3\nweight: 3\n---",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/index.html",
+		`{"@context":"https://schema.org","@type":"CollectionPage","hasPart":[{"@type":"CreativeWork","name":"P1","position":1,"url":"http://example.com/blog/p1/"},{"@type":"CreativeWork","name":"P2","position":2,"url":"http://example.com/blog/p2/"}],"name":"Blog - Page 1 of 2","numberOfItems":3,"position":1,"url":"http://example.com/blog/"}`)
+	b.AssertFileContent("public/blog/page/2/index.html",
+		`"hasPart":[{"@type":"CreativeWork","name":"P3","position":3,"url":"http://example.com/blog/p3/"}]`,
+		`"isPartOf":{"@type":"CollectionPage","url":"http://example.com/blog/"}`,
+		`"name":"Blog - Page 2 of 2"`)
+	require.Equal(t, "Single:", b.FileContent("public/blog/p1/index.html"))
+
+	require.Equal(t, uint64(3), logger.WarnCounter.Count())
+	require.Contains(t, warnings.String(), `The schema_collection.html template in "blog/p1.md" must be called with a dict with the page and its paginator`)
+}
+
+func TestEmbeddedTemplatesOpenGraphSeries(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name        string
+		config      string
+		expectLinks int
+	}{
+		{"Default", `
+[taxonomies]
+series = "series"
+`, 3},
+		{"Custom", `
+[taxonomies]
+saga = "saga"
+[params.opengraph]
+seriesTaxonomy = "saga"
+seriesLimit = 2
+`, 1},
+		{"Missing taxonomy", `
+[params.opengraph]
+seriesTaxonomy = "saga"
+`, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+			for i := 1; i <= 4; i++ {
+				b.WithContent(fmt.Sprintf("p%d.md", i), fmt.Sprintf(`---
+title: P%d
+weight: %d
+series: ["s1"]
+saga: ["s1"]
+---
+`, i, i))
+			}
+
+			b.Build(BuildCfg{})
+
+			content := b.FileContent("public/p1/index.html")
+			require.Equal(t, test.expectLinks, strings.Count(content, `property="og:see_also"`), content)
+		})
+	}
+}
+
+func TestEmbeddedTemplatesOpenGraphImageAspect(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.opengraph]
+imageAspect = "1.91:1"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent("bundle/index.md", `---
+title: Bundle
+images: ["sunset.jpg", "https://example.org/remote.jpg"]
+---
+`)
+	b.WithSunset("content/bundle/sunset.jpg")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/bundle/index.html",
+		`<meta property="og:image" content="http://example.com/bundle/sunset_hu`,
+		`_1200x628_fill_q75_box_center.jpg" />
+<meta property="og:image:width" content="1200" />
+<meta property="og:image:height" content="628" />
+<meta property="og:image:type" content="image/jpeg" />`,
+		`<meta property="og:image" content="https://example.org/remote.jpg" />
+<meta property="og:image:type" content="image/jpeg" />`)
+}
+
+func TestEmbeddedTemplatesOpenGraphInvalidImageAspect(t *testing.T) {
+	t.Parallel()
+
+	for _, aspect := range []string{`"wide"`, `"0"`, `"16:0"`} {
+		var warnings bytes.Buffer
+		logger := loggers.NewLogger(jww.LevelWarn, jww.LevelError, &warnings, ioutil.Discard, false)
+		b := newTestSitesBuilder(t).WithLogger(logger)
+		b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.opengraph]
+imageAspect = `+aspect+`
+`)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+		b.WithContent("bundle/index.md", `---
+title: Bundle
+images: ["sunset.jpg"]
+---
+`)
+		b.WithSunset("content/bundle/sunset.jpg")
+
+		b.Build(BuildCfg{})
+
+		// The image isn't cropped.
+		b.AssertFileContent("public/bundle/index.html", `<meta property="og:image:width" content="900" />`)
+		require.Equal(t, uint64(1), logger.WarnCounter.Count(), aspect)
+		require.Contains(t, warnings.String(), "params.opengraph.imageAspect must be a positive number or ratio", aspect)
+	}
+}
+
+func TestEmbeddedTemplatesSocialImageProcessing(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config string
+		spec   string
+	}{
+		{"[params.social.image]\nfit = \"fill\"", "_1200x630_fill_q80_box_center.jpg"},
+		{"[params.social.image]\nwidth = 600\nheight = 400\nfit = \"fit\"\nquality = 60", "_600x400_fit_q60_box.jpg"},
+		{"[params.social.image]\nwidth = 600", "_600x315_fill_q80_box_center.jpg"},
+		{"[params.images]\nsrcsetWidths = [400, 800]\n[params.social.image]\nfit = \"fill\"", "_800x420_fill_q80_box_center.jpg"},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}{{ template "_internal/schema.html" . }}`)
+		b.WithContent("bundle/index.md", `---
+title: Bundle
+images: ["sunset.jpg", "https://example.org/remote.jpg"]
+---
+`)
+		b.WithSunset("content/bundle/sunset.jpg")
+
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/bundle/index.html")
+		require.Equal(t, 3, strings.Count(content, test.spec), content)
+		require.Contains(t, content, `<meta property="og:image" content="https://example.org/remote.jpg" />`)
+		require.Contains(t, content, `<meta itemprop="image" content="https://example.org/remote.jpg">`)
+	}
+}
+
+func TestEmbeddedTemplatesSocialImageInvalidFit(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.social.image]
+fit = "stretch"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/twitter_cards.html" . }}`)
+	b.WithContent("p1.md", "---\ntitle: p1\nimages: [\"/img/a.png\"]\n---\n")
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `params.social.image.fit must be one of fill, fit or resize, got "stretch"`)
+}
+
+func TestEmbeddedTemplatesSocialDescription(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config   string
+		expected []string
+	}{
+		{"", []string{
+			`<meta property="og:description" content="Tom &amp; Jerry’s bold adventure continues in this description, which is long enough to need some truncation to fit into the link previews of the social networks that want descriptions of around two …" />`,
+			`<meta name="twitter:description" content="Tom &amp; Jerry’s bold adventure continues in this description, which is long enough to need some truncation to fit into the link previews of the social networks that want descriptions of around two …"/>`,
+		}},
+		{"[params.opengraph]\ndescriptionLength = 20", []string{
+			`<meta property="og:description" content="Tom &amp; Jerry’s bold …" />`,
+			`<meta name="twitter:description" content="Tom &amp; Jerry’s bold …"/>`,
+		}},
+		{"[params.opengraph]\ndescriptionLength = 20\n[params.twitter]\ndescriptionLength = 30", []string{
+			`<meta property="og:description" content="Tom &amp; Jerry’s bold …" />`,
+			`<meta name="twitter:description" content="Tom &amp; Jerry’s bold adventure …"/>`,
+		}},
+		{"[params.twitter]\ndescriptionLength = 0", []string{
+			`<meta property="og:description" content="Tom &amp; Jerry’s bold adventure continues in this description, which is long enough to need some truncation to fit into the link previews of the social networks that want descriptions of around two …" />`,
+			`<meta name="twitter:description" content="Tom &amp; Jerry’s bold adventure continues in this description, which is long enough to need some truncation to fit into the link previews of the social networks that want descriptions of around two hundred characters or so."/>`,
+		}},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+		b.WithContent(
+			"long.md", `---
+title: Long
+---
+Tom &amp; Jerry's **bold** adventure continues in this description,
+which is long enough to need some truncation to fit into the link previews of the social networks that want descriptions of around two hundred characters or so.
+`,
+			"empty.md", `---
+title: Empty
+---
+`,
+		)
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/long/index.html", test.expected...)
+
+		content := b.FileContent("public/empty/index.html")
+		require.NotContains(t, content, "og:description", test.config)
+		require.NotContains(t, content, "twitter:description", test.config)
+	}
+}
+
+func TestEmbeddedTemplatesSocialTitle(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config   string
+		expected []string
+	}{
+		{"", []string{
+			`<meta property="og:title" content="Tom &amp; Jerry and the extraordinarily long adventure of the cat and the mouse who never seem to …" />`,
+			`<meta name="twitter:title" content="Tom &amp; Jerry and the extraordinarily long adventure of the cat and the …"/>`,
+		}},
+		{"[params.opengraph]\ntitleLength = 20\n[params.twitter]\ntitleLength = 0", []string{
+			`<meta property="og:title" content="Tom &amp; Jerry and the …" />`,
+			`<meta name="twitter:title" content="Tom &amp; Jerry and the extraordinarily long adventure of the cat and the mouse who never seem to stop chasing each other"/>`,
+		}},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `baseURL = "http://example.com/"
+title = "My Site"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+		b.WithContent(
+			"long.md", "---\ntitle: Tom & Jerry and the extraordinarily long adventure of the cat and the mouse who never seem to stop chasing each other\n---\n",
+			"untitled.md", "---\ntitle: \"\"\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/long/index.html", test.expected...)
+		b.AssertFileContent("public/untitled/index.html",
+			`<meta property="og:title" content="My Site" />`,
+			`<meta name="twitter:title" content="My Site"/>`,
+		)
+	}
+}
+
+func TestEmbeddedTemplatesOpenGraphSections(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: p1\nsections: [reviews, blog, guides]\ntags: [hugo]\n---\n",
+		"blog/p2.md", "---\ntitle: p2\n---\n",
+		"blog/p3.md", "---\ntitle: p3\nsections: reviews\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/p1/index.html", `<meta property="article:section" content="blog" />
+<meta property="article:section" content="reviews" />
+<meta property="article:section" content="guides" />
+<meta property="article:tag" content="hugo" />`)
+	require.Equal(t, 1, strings.Count(b.FileContent("public/blog/p2/index.html"), "article:section"))
+	b.AssertFileContent("public/blog/p3/index.html", `<meta property="article:section" content="blog" />
+<meta property="article:section" content="reviews" />`)
+}
+
+func TestEmbeddedTemplatesOpenGraphTermDescription(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+defaultContentLanguage = "en"
+enableMissingTranslationPlaceholders = true
+[params]
+description = "The site"
+[languages.en]
+weight = 1
+[languages.fr]
+weight = 2
+[languages.de]
+weight = 3
+`)
+	b.WithTemplatesAdded(
+		"_default/taxonomy.html", `{{ template "_internal/opengraph.html" . }}`,
+		"_default/terms.html", `{{ template "_internal/opengraph.html" . }}`,
+	)
+	b.WithSourceFile("i18n/fr.toml", `
+[opengraphTermDescription]
+one = "{{ .Count }} article avec l'étiquette {{ .Term }}"
+other = "{{ .Count }} articles avec l'étiquette {{ .Term }}"
+`)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [Go, Hugo]\ncategories: [News]\n---\n",
+		"p2.md", "---\ntitle: p2\ntags: [Go]\n---\n",
+		"p1.fr.md", "---\ntitle: p1\ntags: [Go]\n---\n",
+		"p2.fr.md", "---\ntitle: p2\ntags: [Go]\n---\n",
+		"p3.fr.md", "---\ntitle: p3\ntags: [Hugo]\n---\n",
+		"p1.de.md", "---\ntitle: p1\ntags: [Go]\n---\n",
+		"tags/hugo/_index.md", "---\ntitle: Hugo\ndescription: All about Hugo\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/tags/go/index.html", `<meta property="og:description" content="2 posts with the tag &#39;Go&#39;" />`)
+	b.AssertFileContent("public/categories/news/index.html", `<meta property="og:description" content="1 post with the category &#39;News&#39;" />`)
+	b.AssertFileContent("public/tags/hugo/index.html", `<meta property="og:description" content="All about Hugo" />`)
+	b.AssertFileContent("public/tags/index.html", `<meta property="og:description" content="The site" />`)
+	b.AssertFileContent("public/fr/tags/go/index.html", `<meta property="og:description" content="2 articles avec l&#39;étiquette Go" />`)
+	b.AssertFileContent("public/fr/tags/hugo/index.html", `<meta property="og:description" content="1 article avec l&#39;étiquette Hugo" />`)
+	// No translation, so the English default.
+	b.AssertFileContent("public/de/tags/go/index.html", `<meta property="og:description" content="1 post with the tag &#39;Go&#39;" />`)
+}
+
+func TestEmbeddedTemplatesOpenGraphFacebook(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		config  string
+		expect  string
+		missing string
+	}{
+		{"App ID and admins", `
+[social]
+facebook_admin = "12345"
+[services.facebook]
+appID = "67890"
+`, `<meta property="fb:admins" content="12345" />
+<meta property="fb:app_id" content="67890" />`, ""},
+		{"App ID", "\n[services.facebook]\nappID = \"67890\"", `<meta property="fb:app_id" content="67890" />`, "fb:admins"},
+		{"None", "", "", "fb:"},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `baseURL = "http://example.com/"`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+			b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+			b.Build(BuildCfg{})
+
+			content := b.FileContent("public/p1/index.html")
+			require.Contains(t, content, test.expect)
+			if test.missing != "" {
+				require.NotContains(t, content, test.missing)
+			}
+		})
+	}
+}
+
+func TestEmbeddedTemplatesOpenGraphLocale(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+defaultContentLanguage = "en"
+[params.opengraph.locales]
+nb = "nb_NO"
+[languages]
+[languages.en]
+languageCode = "en-us"
+weight = 1
+[languages.de]
+weight = 2
+[languages.pt]
+languageCode = "pt_br"
+weight = 3
+[languages.nb]
+weight = 4
+[languages.xx]
+weight = 5
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent(
+		"p1.en.md", "---\ntitle: p1\n---\n",
+		"p2.en.md", "---\ntitle: p2\nlocale: en_GB\n---\n",
+		"p1.de.md", "---\ntitle: p1\n---\n",
+		"p1.pt.md", "---\ntitle: p1\n---\n",
+		"p1.nb.md", "---\ntitle: p1\n---\n",
+		"p1.xx.md", "---\ntitle: p1\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `<meta property="og:locale" content="en_US" />`)
+	b.AssertFileContent("public/p2/index.html", `<meta property="og:locale" content="en_GB" />`)
+	b.AssertFileContent("public/de/p1/index.html", `<meta property="og:locale" content="de_DE" />`)
+	b.AssertFileContent("public/pt/p1/index.html", `<meta property="og:locale" content="pt_BR" />`)
+	b.AssertFileContent("public/nb/p1/index.html", `<meta property="og:locale" content="nb_NO" />`)
+	require.NotContains(t, b.FileContent("public/xx/p1/index.html"), "og:locale")
+}
+
+func TestEmbeddedTemplatesOpenGraphType(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.opengraph.types]
+video = "video.other"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`, "_default/list.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent(
+		"post.md", "---\ntitle: Post\ndate: 2019-02-03\n---\n",
+		"video/clip.md", "---\ntitle: Clip\ndate: 2019-02-03\nvideo:\n  duration: 90\n  tag: [cats, dogs]\n---\n",
+		"video/_index.md", "---\ntitle: Videos\n---\n",
+		"about/me.md", "---\ntitle: Me\nogType: profile\nprofile:\n  first_name: Jane\n  username: jdoe\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/post/index.html",
+		`<meta property="og:type" content="article" />`,
+		`<meta property="article:published_time" content="2019-02-03T00:00:00+00:00" />`)
+	b.AssertFileContent("public/video/clip/index.html", `<meta property="og:type" content="video.other" />
+<meta property="video:duration" content="90" />
+<meta property="video:tag" content="cats" />
+<meta property="video:tag" content="dogs" />`)
+	b.AssertFileContent("public/video/index.html", `<meta property="og:type" content="website" />`)
+	b.AssertFileContent("public/about/me/index.html", `<meta property="og:type" content="profile" />
+<meta property="profile:first_name" content="Jane" />
+<meta property="profile:username" content="jdoe" />`)
+
+	for _, filename := range []string{"public/video/clip/index.html", "public/about/me/index.html"} {
+		require.NotContains(t, b.FileContent(filename), "article:", filename)
+	}
+}
+
+func TestEmbeddedTemplatesOpenGraphExpirationTime(t *testing.T) {
+	t.Parallel()
+
+	for _, enabled := range []bool{true, false} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "http://example.com/"
+[params.opengraph]
+expirationTime = %t
+`, enabled))
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`, "_default/list.html", `{{ template "_internal/opengraph.html" . }}`)
+		b.WithContent(
+			"sale.md", "---\ntitle: Sale\ndate: 2019-02-03\nexpiryDate: 2099-03-04T10:20:30Z\n---\n",
+			"post.md", "---\ntitle: Post\ndate: 2019-02-03\n---\n",
+			"promo/_index.md", "---\ntitle: Promo\nexpiryDate: 2099-03-04T10:20:30Z\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		sale := b.FileContent("public/sale/index.html")
+		if enabled {
+			b.AssertFileContent("public/sale/index.html", `<meta property="article:modified_time" content="2019-02-03T00:00:00+00:00" />
+<meta property="article:expiration_time" content="2099-03-04T10:20:30+00:00" />`)
+		} else {
+			require.NotContains(t, sale, "article:expiration_time")
+		}
+		require.NotContains(t, b.FileContent("public/post/index.html"), "article:expiration_time")
+		require.NotContains(t, b.FileContent("public/promo/index.html"), "article:expiration_time")
+	}
+}
+
+func TestEmbeddedTemplatesOpenGraphImagePrecedence(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params]
+images = ["/site.png"]
+`)
+	b.WithTemplatesAdded(
+		"_default/single.html", `{{ template "_internal/opengraph.html" . }}`,
+		"_default/single.amp.html", `{{ template "_internal/opengraph.html" . }}`,
+	)
+	b.WithContent(
+		"ogimage.md", `---
+title: ogImage
+ogImage: /share.png
+images: ["/inline.png"]
+---
+`,
+		"performat.md", `---
+title: Per output format
+outputs: [html, amp]
+ogImage:
+  html: /share.png
+  amp: /share-amp.png
+---
+`,
+		"images.md", `---
+title: images
+images: ["/inline.png"]
+---
+`,
+		"bundle/index.md", `---
+title: Featured
+---
+`,
+		"site.md", `---
+title: Site
+---
+`,
+	)
+	b.WithSunset("content/bundle/my-cover.jpg")
+
+	b.Build(BuildCfg{})
+
+	image := func(filename, expect string) {
+		content := b.FileContent(filename)
+		require.Equal(t, 1, strings.Count(content, `property="og:image"`), content)
+		require.Contains(t, content, fmt.Sprintf(`<meta property="og:image" content="%s" />`, expect))
+	}
+
+	image("public/ogimage/index.html", "http://example.com/share.png")
+	image("public/performat/index.html", "http://example.com/share.png")
+	image("public/amp/performat/index.html", "http://example.com/share-amp.png")
+	image("public/images/index.html", "http://example.com/inline.png")
+	image("public/bundle/index.html", "http://example.com/bundle/my-cover.jpg")
+	image("public/site/index.html", "http://example.com/site.png")
+}
+
+func TestEmbeddedTemplatesSocialMaxImages(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config           string
+		ogCount          int
+		siteOgCount      int
+		twitterCount     int
+		twitterLargeCard bool
+	}{
+		{"", 6, 2, 1, true},
+		{"[params.opengraph]\nmaxImages = 1\n[params.twitter]\nmaxImages = 3", 1, 1, 3, true},
+		{"[params.opengraph]\nmaxImages = 0\n[params.twitter]\nmaxImages = 0", 0, 0, 0, false},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params]
+images = ["/site1.png", "/site2.png"]
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+		b.WithContent(
+			"many.md", `---
+title: Many
+images: ["/1.png", "/2.png", "/3.png", "/4.png", "/5.png", "/6.png", "/7.png"]
+---
+`,
+			"site.md", `---
+title: Site
+---
+`,
+		)
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/many/index.html")
+		require.Equal(t, test.ogCount, strings.Count(content, `property="og:image"`), test.config)
+		require.Equal(t, test.twitterCount, strings.Count(content, `name="twitter:image"`), test.config)
+		require.Equal(t, test.twitterLargeCard, strings.Contains(content, `content="summary_large_image"`), test.config)
+		if test.twitterCount > 0 {
+			require.Contains(t, content, `<meta name="twitter:image" content="http://example.com/1.png"/>`)
+		}
+
+		// The limit applies to the site images fallback, too.
+		content = b.FileContent("public/site/index.html")
+		require.Equal(t, test.siteOgCount, strings.Count(content, `property="og:image"`), test.config)
+	}
+}
+
+func TestEmbeddedTemplatesSocialImageBaseURL(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.opengraph]
+imageBaseURL = "https://cdn.example.org/social/"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+	b.WithContent(
+		"images.md", `---
+title: images
+images: ["/img/a.png", "https://other.org/b.png"]
+---
+`,
+		"bundle/index.md", `---
+title: Bundle
+---
+`,
+	)
+	b.WithSunset("content/bundle/featured.jpg")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/images/index.html",
+		`<meta property="og:image" content="https://cdn.example.org/social/img/a.png" />`,
+		`<meta property="og:image" content="https://other.org/b.png" />`,
+		`<meta name="twitter:image" content="https://cdn.example.org/social/img/a.png"/>`,
+	)
+	b.AssertFileContent("public/bundle/index.html",
+		`<meta property="og:image" content="https://cdn.example.org/social/bundle/featured.jpg" />`,
+		`<meta name="twitter:image" content="https://cdn.example.org/social/bundle/featured.jpg"/>`,
+	)
+}
+
+func TestEmbeddedTemplatesSocialImageBaseURLNotAbsolute(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.opengraph]
+imageBaseURL = "/social"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}`)
+	b.WithContent("p1.md", "---\ntitle: p1\nimages: [\"/img/a.png\"]\n---\n")
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `params.opengraph.imageBaseURL must be an absolute URL, got "/social"`)
+}
+
+func TestEmbeddedTemplatesSchemaArticle(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "The Times"
+[author]
+name = "Site Author"
+[params.schema.types]
+news = "NewsArticle"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/schema_article.html" . }}{{ template "_internal/schema.html" . }}`)
+	b.WithContent(
+		"post.md", `---
+title: Post
+date: 2019-02-03T10:20:30Z
+description: A post.
+---
+`,
+		"news/storm.md", `---
+title: Storm
+date: 2019-02-03T10:20:30Z
+lastmod: 2019-02-04T10:20:30Z
+author: Jane Doe
+images: ["/storm.jpg"]
+tags: [weather, storm]
+description: A storm is coming.
+schema:
+  dateline: Bergen, Norway
+  printSection: A1
+---
+`,
+		"news/blog.md", `---
+title: Blog
+date: 2019-02-03T10:20:30Z
+description: Not news.
+schema:
+  type: BlogPosting
+  dateline: Bergen
+---
+`,
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/post/index.html",
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","author":{"@type":"Person","name":"Site Author"},"dateModified":"2019-02-03T10:20:30+00:00","datePublished":"2019-02-03T10:20:30+00:00","description":"A post.","headline":"Post","mainEntityOfPage":"http://example.com/post/","publisher":{"@type":"Organization","name":"The Times"},"wordCount":0}</script>`,
+	)
+	b.AssertFileContent("public/news/storm/index.html",
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"NewsArticle","articleSection":"news","author":{"@type":"Person","name":"Jane Doe"},"dateModified":"2019-02-04T10:20:30+00:00","datePublished":"2019-02-03T10:20:30+00:00","dateline":"Bergen, Norway","description":"A storm is coming.","headline":"Storm","image":["http://example.com/storm.jpg"],"keywords":"weather,storm","mainEntityOfPage":"http://example.com/news/storm/","printSection":"A1","publisher":{"@type":"Organization","name":"The Times"},"wordCount":0}</script>`,
+		`<meta itemprop="dateline" content="Bergen, Norway">
+<meta itemprop="printSection" content="A1">`,
+	)
+
+	content := b.FileContent("public/news/blog/index.html")
+	require.Contains(t, content, `"@type":"BlogPosting"`)
+	require.NotContains(t, content, "dateline")
+}
+
+func TestEmbeddedTemplatesSchemaArticleAuthors(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "The Times"
+[author]
+name = "Site Author"
+[authors.alice]
+displayName = "Alice Smith"
+[authors.alice.social]
+website = "alice.example.org"
+twitter = "@alice"
+github = "https://github.com/alice-smith"
+mastodon = "alice"
+[authors.bob]
+givenName = "Bob"
+familyName = "Jones"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/schema_article.html" . }}`)
+	b.WithContent(
+		"single.md", "---\ntitle: Single\nauthors: [alice]\n---\n",
+		"multi.md", "---\ntitle: Multi\nauthors: [alice, bob, carol]\n---\n",
+		"fallback.md", "---\ntitle: Fallback\n---\n",
+		"map.md", "---\ntitle: Map\nauthor:\n  name: Dora\n  twitter: dora\n---\n",
+		"maps.md", "---\ntitle: Maps\nauthor:\n- name: Jane Doe\n  twitter: \"@janedoe\"\n- name: John Doe\n- twitter: nameless\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/map/index.html", `"author":{"@type":"Person","name":"Dora"}`)
+	b.AssertFileContent("public/maps/index.html", `"author":[{"@type":"Person","name":"Jane Doe"},{"@type":"Person","name":"John Doe"}]`)
+	b.AssertFileContent("public/single/index.html",
+		`"author":{"@type":"Person","name":"Alice Smith","sameAs":["https://github.com/alice-smith","https://twitter.com/alice"],"url":"https://alice.example.org"}`,
+	)
+	b.AssertFileContent("public/multi/index.html",
+		`"author":[{"@type":"Person","name":"Alice Smith","sameAs":["https://github.com/alice-smith","https://twitter.com/alice"],"url":"https://alice.example.org"},{"@type":"Person","name":"Bob Jones"},{"@type":"Person","name":"carol"}]`,
+	)
+	b.AssertFileContent("public/fallback/index.html",
+		`"author":{"@type":"Person","name":"Site Author"}`,
+	)
+}
+
+func TestEmbeddedTemplatesSchemaArticleInvalidType(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	logger := loggers.NewLogger(jww.LevelWarn, jww.LevelError, &warnings, ioutil.Discard, false)
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/schema_article.html" . }}`)
+	b.WithContent("p1.md", "---\ntitle: p1\nschema:\n  type: Recipe\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `"@type":"Article"`)
+	require.Equal(t, uint64(1), logger.WarnCounter.Count())
+	require.Contains(t, warnings.String(), `The schema type of "p1.md" must be one of Article, NewsArticle or BlogPosting, got "Recipe"; using Article`)
+}
+
+func TestEmbeddedTemplatesSchemaOtherType(t *testing.T) {
+	t.Parallel()
+
+	// Only schema_article.html is limited to the article types.
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/schema.html" . }}`)
+	b.WithContent("p1.md", "---\ntitle: p1\nschema:\n  type: Recipe\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `<meta itemprop="name" content="p1">`)
+}
+
+func TestEmbeddedTemplatesSchemaArticleImageDimensions(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{"Default", "", `"image":[{"@type":"ImageObject","height":562,"url":"http://example.com/blog/p1/sunset.jpg","width":900},"https://example.org/remote.jpg"]`},
+		{"Disabled", `
+[params.schema]
+imageDimensions = false
+`, `"image":["http://example.com/sunset.jpg","https://example.org/remote.jpg"]`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `baseURL = "http://example.com/"`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/schema_article.html" . }}`)
+			b.WithContent("blog/p1/index.md", "---\ntitle: p1\nimages: [sunset.jpg, \"https://example.org/remote.jpg\"]\n---\n")
+			b.WithSunset("content/blog/p1/sunset.jpg")
+			b.Build(BuildCfg{})
+
+			require.Contains(t, b.FileContent("public/blog/p1/index.html"), test.expect)
+		})
+	}
+}
+
+func TestEmbeddedTemplatesSchemaBreadcrumbs(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "My Site"
+`)
+	templ := `{{ template "_internal/schema_breadcrumbs.html" . }}`
+	b.WithTemplatesAdded(
+		"index.html", "Home:"+templ,
+		"_default/single.html", templ,
+		"_default/list.html", templ,
+		"_default/taxonomy.html", templ,
+		"_default/terms.html", templ,
+	)
+	b.WithContent(
+		"p1.md", "---\ntitle: Root\n---\n",
+		"blog/_index.md", "---\ntitle: Blog\n---\n",
+		"blog/2019/_index.md", "---\ntitle: Archive\n---\n",
+		"blog/2019/p2.md", "---\ntitle: Post\ntags: [Go]\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	item := func(position int, name, url string) string {
+		return fmt.Sprintf(`{"@type":"ListItem","item":"http://example.com/%s","name":"%s","position":%d}`, url, name, position)
+	}
+	list := func(items ...string) string {
+		return `<script type="application/ld+json">{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[` + strings.Join(items, ",") + `]}</script>`
+	}
+
+	b.AssertFileContent("public/blog/2019/p2/index.html", list(item(1, "My Site", ""), item(2, "Blog", "blog/"), item(3, "Archive", "blog/2019/"), item(4, "Post", "blog/2019/p2/")))
+	b.AssertFileContent("public/p1/index.html", list(item(1, "My Site", ""), item(2, "Root", "p1/")))
+	b.AssertFileContent("public/tags/go/index.html", list(item(1, "My Site", ""), item(2, "Tags", "tags/"), item(3, "Go", "tags/go/")))
+	b.AssertFileContent("public/tags/index.html", list(item(1, "My Site", ""), item(2, "Tags", "tags/")))
+	require.NotContains(t, b.FileContent("public/index.html"), "BreadcrumbList")
+}
+
+func TestEmbeddedTemplatesCanonicalTrailingSlash(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		expect []string
+	}{
+		{"Unset", "", []string{
+			`<meta property="og:url" content="http://example.com/blog/p1/" />`,
+			`"mainEntityOfPage":"http://example.com/blog/p1/"`,
+			`"item":"http://example.com/blog/"`,
+			`A: http://example.com/a/|http://example.com/a/b?q=1#top|http://example.com/index.xml|http://example.com/`,
+		}},
+		{"Remove", "\n[params]\ncanonicalTrailingSlash = false", []string{
+			`<meta property="og:url" content="http://example.com/blog/p1" />`,
+			`"mainEntityOfPage":"http://example.com/blog/p1"`,
+			`"item":"http://example.com/blog"`,
+			`"item":"http://example.com/"`,
+			`A: http://example.com/a|http://example.com/a/b?q=1#top|http://example.com/index.xml|http://example.com/`,
+		}},
+		{"Add", "\n[params]\ncanonicalTrailingSlash = true", []string{
+			`<meta property="og:url" content="http://example.com/blog/p1/" />`,
+			`A: http://example.com/a/|http://example.com/a/b/?q=1#top|http://example.com/index.xml|http://example.com/`,
+		}},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `baseURL = "http://example.com/"`+test.config)
+			b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}
+{{ template "_internal/schema_article.html" . }}
+{{ template "_internal/schema_breadcrumbs.html" . }}
+{{- $s := newScratch }}{{ $urls := slice }}
+{{- range slice "http://example.com/a/" "http://example.com/a/b?q=1#top" "http://example.com/index.xml" "http://example.com/" }}
+{{- template "__canonical_url" (dict "page" $ "url" . "scratch" $s) }}{{ $urls = $urls | append ($s.Get "url") }}{{ end }}
+A: {{ delimit $urls "|" | safeHTML }}`)
+			b.WithContent("blog/p1.md", "---\ntitle: p1\n---\n")
+			b.Build(BuildCfg{})
+
+			b.AssertFileContent("public/blog/p1/index.html", test.expect...)
+		})
+	}
+}
+
+func TestEmbeddedTemplatesSchemaKeywords(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		tags   string
+		expect string
+	}{
+		{"Single", "", "[hugo]", `<meta itemprop="keywords" content="hugo" />`},
+		{"Multiple", "", "[hugo, go, templates]", `<meta itemprop="keywords" content="hugo,go,templates" />`},
+		{"None", "", "[]", ""},
+		{"Separator and prefix", `
+[params.schema]
+keywordsSeparator = "; "
+keywordsPrefix = "tag:"
+`, "[hugo, go]", `<meta itemprop="keywords" content="tag:hugo; tag:go" />`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded(
+				"_default/single.html", `{{ template "_internal/schema.html" . }}`,
+				"_default/article.html", `{{ template "_internal/schema_article.html" . }}`,
+			)
+			b.WithContent(
+				"p1.md", "---\ntitle: p1\ntags: "+test.tags+"\n---\n",
+				"p2.md", "---\ntitle: p2\nlayout: article\ntags: "+test.tags+"\n---\n",
+			)
+			b.Build(BuildCfg{})
+
+			content := b.FileContent("public/p1/index.html")
+			if test.expect == "" {
+				require.NotContains(t, content, "keywords")
+				require.NotContains(t, b.FileContent("public/p2/index.html"), "keywords")
+			} else {
+				b.AssertFileContent("public/p1/index.html", test.expect)
+				keywords := strings.TrimSuffix(strings.Split(test.expect, `content="`)[1], `" />`)
+				b.AssertFileContent("public/p2/index.html", fmt.Sprintf(`"keywords":%q`, keywords))
+			}
+		})
+	}
+}
+
+func TestEmbeddedTemplatesSchemaKeywordsListPage(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.schema]
+keywordsPrefix = "tag:"
+`)
+	b.WithTemplatesAdded(
+		"index.html", `{{ template "_internal/schema.html" . }}`,
+		"_default/single.html", `{{ template "_internal/schema.html" . }}`,
+	)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [hugo, go]\ncategories: [news]\n---\n",
+		"p2.md", "---\ntitle: p2\ntags: [hugo]\ncategories: [go]\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	// A list page gets all the site's taxonomy terms, each only once.
+	b.AssertFileContent("public/index.html", `<meta itemprop="keywords" content="tag:go,tag:news,tag:hugo" />`)
+	b.AssertFileContent("public/p2/index.html", `<meta itemprop="keywords" content="tag:hugo" />`)
+}
+
+func TestEmbeddedTemplatesSchemaSearch(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{"Absolute", `
+[params.search]
+url = "https://example.com/search?q={search_term_string}"
+`, `<script type="application/ld+json">{"@context":"https://schema.org","@type":"WebSite","name":"Search","potentialAction":{"@type":"SearchAction","query-input":"required name=search_term_string","target":"https://example.com/search?q={search_term_string}"},"url":"http://example.com/"}</script>`},
+		{"Relative", `
+[params.search]
+url = "/search/?q={search_term_string}"
+`, `"target":"http://example.com/search/?q={search_term_string}"`},
+		{"None", "", ""},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "Search"
+`+test.config)
+			b.WithTemplatesAdded(
+				"index.html", `Home:{{ template "_internal/schema_search.html" . }}`,
+				"_default/single.html", `Single:{{ template "_internal/schema_search.html" . }}`,
+			)
+			b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+			b.Build(BuildCfg{})
+
+			if test.expect == "" {
+				require.Equal(t, "Home:", b.FileContent("public/index.html"))
+			} else {
+				b.AssertFileContent("public/index.html", test.expect)
+			}
+			require.Equal(t, "Single:", b.FileContent("public/p1/index.html"))
+		})
+	}
+}
+
+func TestEmbeddedTemplatesSchemaSearchNoPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params.search]
+url = "/search/"
+`)
+	b.WithTemplatesAdded("index.html", `{{ template "_internal/schema_search.html" . }}`)
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `params.search.url must contain the {search_term_string} placeholder, got "/search/"`)
+}
+
+func TestEmbeddedTemplatesAMPLinks(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[outputs]
+page = ["HTML", "AMP"]
+`)
+	b.WithTemplatesAdded(
+		"_default/single.html", `HTML:{{ template "_internal/amp_links.html" . }}`,
+		"_default/single.amp.html", `AMP:{{ template "_internal/amp_links.html" . }}`,
+		"index.html", `Home:{{ template "_internal/amp_links.html" . }}`,
+	)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/p1/index.html", `HTML:
+<link rel="amphtml" href="http://example.com/amp/p1/" />`)
+	b.AssertFileContent("public/amp/p1/index.html", `AMP:
+<link rel="canonical" href="http://example.com/p1/" />`)
+	require.Equal(t, "Home:", b.FileContent("public/index.html"))
+
+	b = newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[params]
+canonicalTrailingSlash = false
+[outputs]
+page = ["HTML", "AMP"]
+`)
+	b.WithTemplatesAdded("_default/single.amp.html", `AMP:{{ template "_internal/amp_links.html" . }}`)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/amp/p1/index.html", `<link rel="canonical" href="http://example.com/p1" />`)
+}
+
+func TestEmbeddedTemplatesGoogleAnalytics4(t *testing.T) {
+	t.Parallel()
+
+	for _, async := range []bool{false, true} {
+		// With strict, both consentMode and useSessionStorage are enabled.
+		for _, strict := range []bool{false, true} {
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "http://example.com/"
+googleAnalytics = "G-12345"
+[privacy.googleAnalytics]
+anonymizeIP = true
+respectDoNotTrack = true
+useSessionStorage = %t
+consentMode = %t
+`, strict, strict))
+			name := "google_analytics.html"
+			if async {
+				name = "google_analytics_async.html"
+			}
+			b.WithTemplatesAdded("index.html", fmt.Sprintf(`{{ template "_internal/%s" . }}`, name))
+			b.Build(BuildCfg{})
+
+			// gtag.js is only loaded if Do Not Track isn't enabled.
+			b.AssertFileContent("public/index.html",
+				`function gtag(){dataLayer.push(arguments);}`,
+				`var doNotTrack = (dnt == "1" || dnt == "yes");
+if (!doNotTrack) {
+	var gaScript = document.createElement('script');
+	gaScript.async = true;
+	gaScript.src = 'https://www.googletagmanager.com/gtag/js?id=G-12345';`,
+				`gaConfig['anonymize_ip'] = true;`,
+				`gtag('config', 'G-12345', gaConfig);
+}`,
+			)
+
+			content := b.FileContent("public/index.html")
+			require.NotContains(t, content, "analytics.js")
+			require.NotContains(t, content, "<script async")
+			if strict {
+				b.AssertFileContent("public/index.html", `gaConfig['client_storage'] = 'none';
+		gaConfig['client_id'] = clientId;`)
+			} else {
+				require.NotContains(t, content, "sessionStorage")
+			}
+			if strict {
+				b.AssertFileContent("public/index.html", `gtag('consent', 'default', {
+	'analytics_storage': 'denied',`)
+			} else {
+				require.NotContains(t, content, "consent")
+			}
+		}
+	}
+}
+
+func TestEmbeddedTemplatesTwitterCreator(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"
+[social]
+twitter = "@gohugoio"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/twitter_cards.html" . }}`)
+	b.WithContent(
+		"single.md", "---\ntitle: Single\nauthor:\n  name: Jane\n  twitter: \"@jane\"\n---\n",
+		"multi.md", "---\ntitle: Multi\nauthor:\n- name: Jane\n  twitter: jane\n- name: John\n  twitter: \"@john\"\n- name: Anon\n---\n",
+		"none.md", "---\ntitle: None\nauthor: Jane\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/single/index.html",
+		`<meta name="twitter:site" content="@gohugoio"/>`,
+		`<meta name="twitter:creator" content="@jane"/>`,
+	)
+	b.AssertFileContent("public/multi/index.html",
+		`<meta name="twitter:creator" content="@jane"/>
+<meta name="twitter:creator" content="@john"/>`,
+	)
+	b.AssertFileContent("public/none/index.html",
+		`<meta name="twitter:creator" content="@gohugoio"/>`,
+	)
+
+	require.NotContains(t, b.FileContent("public/single/index.html"), "@@")
+	require.Equal(t, 1, strings.Count(b.FileContent("public/single/index.html"), "twitter:creator"))
+}
+
+func TestEmbeddedTemplatesTwitterCardOverride(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	logger := loggers.NewLogger(jww.LevelWarn, jww.LevelError, &warnings, ioutil.Discard, false)
+
+	b := newTestSitesBuilder(t).WithLogger(logger)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/twitter_cards.html" . }}`)
+	b.WithContent(
+		"auto.md", "---\ntitle: Auto\nimages: [\"/logo.png\"]\n---\n",
+		"summary.md", "---\ntitle: Summary\nimages: [\"/logo.png\"]\ntwitter:\n  card: summary\n---\n",
+		"player.md", "---\ntitle: Player\ntwitter:\n  card: Player\n---\n",
+		"invalid.md", "---\ntitle: Invalid\nimages: [\"/logo.png\"]\ntwitter:\n  card: app\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/auto/index.html", `<meta name="twitter:card" content="summary_large_image"/>
+<meta name="twitter:image" content="http://example.com/logo.png"/>`)
+	b.AssertFileContent("public/summary/index.html", `<meta name="twitter:card" content="summary"/>
+<meta name="twitter:image" content="http://example.com/logo.png"/>`)
+	b.AssertFileContent("public/player/index.html", `<meta name="twitter:card" content="player"/>`)
+	b.AssertFileContent("public/invalid/index.html", `<meta name="twitter:card" content="summary_large_image"/>`)
+
+	require.Equal(t, uint64(1), logger.WarnCounter.Count())
+	require.Contains(t, warnings.String(), `Invalid twitter.card "app" in "invalid.md"`)
+}
+
+func TestEmbeddedTemplatesAuthorProfiles(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `baseURL = "http://example.com/"
+[social]
+twitter = "@gohugoio"
+[authors.alice.social]
+twitter = "@alice"
+facebook = "alice.fb"
+[authors.bob.social]
+twitter = "bob"
+facebook = "https://www.facebook.com/bob.fb"
+`)
+	b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+	b.WithContent(
+		"alice.md", "---\ntitle: Alice\nauthors: alice\n---\n",
+		"both.md", "---\ntitle: Both\nauthors: [alice, bob]\n---\n",
+		"none.md", "---\ntitle: None\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/alice/index.html",
+		`<meta property="article:author" content="https://www.facebook.com/alice.fb" />`,
+		`<meta name="twitter:creator" content="@alice"/>`,
+	)
+	require.NotContains(t, b.FileContent("public/alice/index.html"), "@bob")
+	b.AssertFileContent("public/both/index.html",
+		`<meta property="article:author" content="https://www.facebook.com/alice.fb" />
+<meta property="article:author" content="https://www.facebook.com/bob.fb" />`,
+		`<meta name="twitter:creator" content="@alice"/>
+<meta name="twitter:creator" content="@bob"/>`,
+	)
+	none := b.FileContent("public/none/index.html")
+	require.NotContains(t, none, "article:author")
+	require.Contains(t, none, `<meta name="twitter:creator" content="@gohugoio"/>`)
+	require.NotContains(t, none, "@alice")
+}
+
+func TestEmbeddedTemplatesEmptyTaxonomies(t *testing.T) {
+	t.Parallel()
+
+	for _, emitEmpty := range []bool{false, true} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", fmt.Sprintf(`baseURL = "http://example.com/"
+[params.social]
+emitEmptyTags = %t
+`, emitEmpty))
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/schema.html" . }}`)
+		b.WithContent(
+			"untagged.md", "---\ntitle: Untagged\n---\n",
+			"blank.md", "---\ntitle: Blank\ntags: [\"\", \" \"]\nseries: []\n---\n",
+			"single.md", "---\ntitle: Single\ntags: \" hugo \"\nseries: intro\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		for _, name := range []string{"untagged", "blank"} {
+			content := b.FileContent(fmt.Sprintf("public/%s/index.html", name))
+			if emitEmpty {
+				require.Contains(t, content, `<meta property="article:tag" content="" />`)
+				require.Contains(t, content, `<meta itemprop="keywords" content="" />`)
+				require.Equal(t, 2, strings.Count(content, `content=""`), content)
+			} else {
+				require.NotContains(t, content, `content=""`, content)
+				require.NotContains(t, content, "article:tag")
+				require.NotContains(t, content, "keywords")
+			}
+		}
+
+		content := b.FileContent("public/single/index.html")
+		require.NotContains(t, content, `content=""`, content)
+		require.Contains(t, content, `<meta property="article:tag" content="hugo" />`)
+		require.Contains(t, content, `<meta itemprop="keywords" content="hugo" />`)
+	}
+}
+
+func TestEmbeddedTemplatesFeaturedImage(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config   string
+		expected string
+	}{
+		{"", "cover.jpg"},
+		{"[params.social]\nfeaturedImages = [\"*hero*\", \"*cover*\"]", "hero.jpg"},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplatesAdded("_default/single.html", `{{ template "_internal/opengraph.html" . }}{{ template "_internal/twitter_cards.html" . }}`)
+		b.WithContent("bundle/index.md", "---\ntitle: Bundle\n---\n")
+		b.WithSunset("content/bundle/cover.jpg")
+		b.WithSunset("content/bundle/hero.jpg")
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/bundle/index.html",
+			`<meta property="og:image" content="http://example.com/bundle/`+test.expected+`" />
+<meta property="og:image:width" content="900" />
+<meta property="og:image:height" content="562" />
+<meta property="og:image:type" content="image/jpeg" />`,
+			`<meta name="twitter:image" content="http://example.com/bundle/`+test.expected+`"/>`,
+		)
+	}
+}
@@ -678,7 +678,13 @@ func (h *HugoSites) createMissingPages() error {
 				p.getTaxonomyNodeInfo().TransferValues(p)
 			}
 
+			plurals := make([]string, 0, len(taxonomies))
 			for _, plural := range taxonomies {
+				plurals = append(plurals, plural)
+			}
+			sort.Strings(plurals)
+
+			for _, plural := range plurals {
 				if taxonomyTermEnabled {
 					foundTaxonomyTermsPage := false
 					for _, p := range taxonomyTermsPages {
@@ -696,8 +702,21 @@ func (h *HugoSites) createMissingPages() error {
 				}
 
 				if taxonomyEnabled {
-					for termKey := range s.Taxonomies[plural] {
+					for _, info := range s.taxonomyNodes.sortedNodes() {
+						if info.plural != plural || info.termKey == "" {
+							continue
+						}
+
+						terms := s.Taxonomies[plural]
+						if info.intersection {
+							terms = s.taxonomyIntersections[plural]
+						}
+						if _, found := terms[info.termKey]; !found {
+							// A term with a content file, but no pages.
+							continue
+						}
 
+						termKey := info.termKey
 						foundTaxonomyPage := false
 
 						for _, p := range taxonomyPages {
@@ -717,11 +736,6 @@ func (h *HugoSites) createMissingPages() error {
 						}
 
 						if !foundTaxonomyPage {
-							info := s.taxonomyNodes.Get(plural, termKey)
-							if info == nil {
-								panic("no info found")
-							}
-
 							n := s.newTaxonomyPage(info.term, info.plural, info.termKey)
 							info.TransferValues(n)
 							s.workAllPages = append(s.workAllPages, n)
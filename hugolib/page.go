@@ -135,17 +135,26 @@ func (p *pageState) Pages() page.Pages {
 			pages = p.s.RegularPages()
 		case page.KindTaxonomy:
 			termInfo := p.getTaxonomyNodeInfo()
-			taxonomy := p.s.Taxonomies[termInfo.plural].Get(termInfo.termKey)
+			taxonomy := p.s.taxonomyTermPages(termInfo.plural, termInfo.termKey)
 			pages = taxonomy.Pages()
 		case page.KindTaxonomyTerm:
 			plural := p.getTaxonomyNodeInfo().plural
-			// A list of all page.KindTaxonomy pages with matching plural
+			// A list of all page.KindTaxonomy pages with matching plural,
+			// term intersections excluded.
 			for _, p := range p.s.findPagesByKind(page.KindTaxonomy) {
-				if p.SectionsEntries()[0] == plural {
-					pages = append(pages, p)
+				if p.SectionsEntries()[0] != plural {
+					continue
 				}
+				if ps, ok := p.(*pageState); ok {
+					if info := ps.getTaxonomyNodeInfo(); info != nil && info.intersection {
+						continue
+					}
+				}
+				pages = append(pages, p)
 			}
-		case kind404, kindSitemap, kindRobotsTXT:
+		case kindSitemap:
+			pages = p.s.sitemapPages()
+		case kind404, kindRobotsTXT:
 			pages = p.s.Pages()
 		}
 
@@ -42,7 +42,7 @@ func (p *pageData) Data() interface{} {
 			singular := pluralInfo.singular
 			plural := pluralInfo.plural
 			term := termInfo.term
-			taxonomy := p.s.Taxonomies[plural].Get(termInfo.termKey)
+			taxonomy := p.s.taxonomyTermPages(plural, termInfo.termKey)
 
 			p.data[singular] = taxonomy
 			p.data["Singular"] = singular
@@ -59,6 +59,10 @@ func (p *pageData) Data() interface{} {
 			// keep the following just for legacy reasons
 			p.data["OrderedIndex"] = p.data["Terms"]
 			p.data["Index"] = p.data["Terms"]
+		case kindRobotsTXT:
+			if p.s.isEnabled(kindSitemap) {
+				p.data["Sitemap"] = p.s.PathSpec.AbsURL(p.s.siteCfg.sitemap.Filename, false)
+			}
 		}
 
 		// Assign the function to the map to make sure it is lazily initialized
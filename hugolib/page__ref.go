@@ -15,6 +15,8 @@ package hugolib
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/gohugoio/hugo/common/text"
 
@@ -88,7 +90,12 @@ func (p pageRef) ref(argsm map[string]interface{}, source interface{}) (string,
 		return "", nil
 	}
 
-	return s.refLink(args.Path, source, false, args.OutputFormat)
+	link, err := s.refLink(args.Path, source, false, args.OutputFormat)
+	if err != nil || args.TrailingSlash == nil {
+		return link, err
+	}
+
+	return toggleTrailingSlash(link, *args.TrailingSlash), nil
 
 }
 
@@ -106,7 +113,12 @@ func (p pageRef) relRef(argsm map[string]interface{}, source interface{}) (strin
 		return "", nil
 	}
 
-	return s.refLink(args.Path, source, true, args.OutputFormat)
+	link, err := s.refLink(args.Path, source, true, args.OutputFormat)
+	if err != nil || args.TrailingSlash == nil {
+		return link, err
+	}
+
+	return toggleTrailingSlash(link, *args.TrailingSlash), nil
 
 }
 
@@ -114,4 +126,32 @@ type refArgs struct {
 	Path         string
 	Lang         string
 	OutputFormat string
+
+	// TrailingSlash, when set, overrides the site's canonical slash handling
+	// for the resolved link.
+	TrailingSlash *bool
+}
+
+// toggleTrailingSlash adds or removes the trailing slash of the path part of
+// link, keeping any query string and fragment intact. Links pointing to a
+// file (e.g. with uglyURLs) never get a slash added.
+func toggleTrailingSlash(link string, slash bool) string {
+	var suffix string
+	if i := strings.IndexAny(link, "?#"); i != -1 {
+		link, suffix = link[:i], link[i:]
+	}
+
+	if link == "" || link == "/" || strings.HasSuffix(link, "//") {
+		return link + suffix
+	}
+
+	if slash {
+		if !strings.HasSuffix(link, "/") && path.Ext(link) == "" {
+			link += "/"
+		}
+	} else {
+		link = strings.TrimSuffix(link, "/")
+	}
+
+	return link + suffix
 }
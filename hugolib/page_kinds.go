@@ -14,6 +14,8 @@
 package hugolib
 
 import (
+	"strings"
+
 	"github.com/gohugoio/hugo/resources/page"
 )
 
@@ -38,3 +40,14 @@ const (
 
 	pageResourceType = "page"
 )
+
+// canonicalKind returns the kind in allKinds matching k case insensitively,
+// or k if none match. Config keys are lower cased, e.g. "taxonomyterm".
+func canonicalKind(k string) string {
+	for _, kind := range allKinds {
+		if strings.EqualFold(k, kind) {
+			return kind
+		}
+	}
+	return k
+}
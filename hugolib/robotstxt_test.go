@@ -17,6 +17,7 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
 )
 
 const robotTxtTemplate = `User-agent: Googlebot
@@ -40,3 +41,68 @@ func TestRobotsTXTOutput(t *testing.T) {
 	b.AssertFileContent("public/robots.txt", "User-agent: Googlebot")
 
 }
+
+func TestRobotsTXTEmbedded(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "http://example.com/blog/"
+enableRobotsTXT = true
+[robots]
+crawlDelay = 5
+[[robots.rules]]
+userAgent = "BadBot"
+disallow = ["/"]
+[[robots.rules]]
+userAgent = "Googlebot"
+allow = ["/public/"]
+crawlDelay = 2
+`)
+	b.WithTemplates("_default/single.html", "{{ .Title }}")
+	b.WithContent(
+		"private/_index.md", "---\ntitle: Private\nrobots:\n  disallow: true\n---",
+		"private/p1.md", "---\ntitle: P1\n---",
+		"drafts.md", "---\ntitle: Drafts\nrobots:\n  disallow: true\n---",
+		"public.md", "---\ntitle: Public\nrobots:\n  noindex: true\n---",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/robots.txt", `User-agent: *
+Crawl-delay: 5
+Disallow: /blog/drafts/
+Disallow: /blog/private/
+
+User-agent: BadBot
+Disallow: /
+
+User-agent: Googlebot
+Crawl-delay: 2
+Allow: /public/
+
+Sitemap: http://example.com/blog/sitemap.xml`)
+}
+
+func TestRobotsTXTEmbeddedMinimal(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config string
+		expect string
+	}{
+		{`enableRobotsTXT = true`, "User-agent: *\n\nSitemap: http://example.com/sitemap.xml\n"},
+		{`enableRobotsTXT = true
+disableKinds = ["sitemap"]`, "User-agent: *\n"},
+	} {
+		b := newTestSitesBuilder(t)
+		b.WithConfigFile("toml", `baseURL = "http://example.com/"
+`+test.config)
+		b.WithTemplates("_default/single.html", "{{ .Title }}")
+		b.WithContent("p1.md", "---\ntitle: P1\n---")
+
+		b.Build(BuildCfg{})
+
+		require.Equal(t, test.expect, b.FileContent("public/robots.txt"))
+	}
+}
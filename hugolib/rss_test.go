@@ -14,11 +14,17 @@
 package hugolib
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/deps"
+	jww "github.com/spf13/jwalterweatherman"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRSSOutput(t *testing.T) {
@@ -98,3 +104,556 @@ Figure:
 
 	b.AssertFileContent("public/index.xml", "img src=&#34;http://example.com/images/sunset.jpg")
 }
+
+func TestRSSComments(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[services.rss]
+commentsAnchor = "comments"
+`)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ncommentsCount: 3\n---\n",
+		"p2.md", "---\ntitle: p2\n---\n",
+		"p3.md", "---\ntitle: p3\ncomments: false\n---\n",
+	)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		`xmlns:slash="http://purl.org/rss/1.0/modules/slash/"`,
+		"<comments>http://example.com/p1/#comments</comments>\n      <slash:comments>3</slash:comments>",
+		"<comments>http://example.com/p2/#comments</comments>",
+	)
+
+	content := b.FileContent("public/index.xml")
+	if strings.Contains(content, "http://example.com/p3/#comments") {
+		t.Errorf("comments link emitted for page with comments disabled:\n%s", content)
+	}
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+	b.Build(BuildCfg{})
+
+	content = b.FileContent("public/index.xml")
+	if strings.Contains(content, "<comments>") || strings.Contains(content, "xmlns:slash") {
+		t.Errorf("comments emitted without configuration:\n%s", content)
+	}
+}
+
+func TestRSSDateFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, this := range []struct {
+		config   string
+		expected []string
+	}{
+		// The default RFC 822 layout is never translated.
+		{``, []string{"<pubDate>Sun, 03 Feb 2019 10:20:30 +0000</pubDate>", "<lastBuildDate>Sun, 03 Feb 2019"}},
+		{`
+[services.rss]
+dateFormat = "Mon, 02 Jan 2006 15:04:05 MST"
+`, []string{"<pubDate>dim., 03 févr. 2019 10:20:30 UTC</pubDate>", "<lastBuildDate>dim., 03 févr. 2019"}},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`+this.config)
+		b.WithSourceFile("i18n/en.toml", "[Sun]\nother = \"dim.\"\n[Feb]\nother = \"févr.\"\n")
+		b.WithContent("p1.md", "---\ntitle: p1\ndate: 2019-02-03T10:20:30Z\n---\n")
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/index.xml", this.expected...)
+	}
+}
+
+func TestRSSSummaryLength(t *testing.T) {
+	t.Parallel()
+
+	content := "---\ntitle: p1\n---\nFirst *emphasized words* in the post.\n\nSecond paragraph.\n"
+
+	for _, this := range []struct {
+		config   string
+		expected string
+	}{
+		{``, "<description>First emphasized words in the post.\nSecond paragraph.</description>"},
+		{`
+[services.rss]
+summaryLength = 2
+`, "<description>&lt;p&gt;First &lt;em&gt;emphasized …&lt;/em&gt;&lt;/p&gt;</description>"},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`+this.config)
+		b.WithContent("p1.md", content)
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/index.xml", this.expected)
+	}
+}
+
+func TestRSSManualSummary(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[services.rss]
+summaryLength = 2
+manualSummary = true
+`)
+	b.WithContent(
+		"manual.md", "---\ntitle: Manual\n---\nAbove the *fold*.\n<!--more-->\nBelow the fold.\n",
+		"frontmatter.md", "---\ntitle: Front Matter\nsummary: From the front matter.\n---\nThe content.\n",
+		"auto.md", "---\ntitle: Auto\n---\nNo marker in this post.\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		"<description>&lt;div class=&#34;summary-manual&#34;&gt;&lt;p&gt;Above the &lt;em&gt;fold&lt;/em&gt;.&lt;/p&gt;&lt;/div&gt;</description>",
+		"<description>&lt;div class=&#34;summary-manual&#34;&gt;From the front matter.&lt;/div&gt;</description>",
+		"<description>&lt;div class=&#34;summary-auto&#34;&gt;&lt;p&gt;No marker …&lt;/p&gt;&lt;/div&gt;</description>",
+	)
+}
+
+func TestRSSGUID(t *testing.T) {
+	t.Parallel()
+
+	for _, this := range []struct {
+		config   string
+		expected []string
+	}{
+		{``, []string{"<guid>http://example.com/p1/</guid>", "<guid>http://example.com/p2/</guid>"}},
+		{`
+[services.rss]
+guid = "stable"
+guidPrefix = "tag:example.com,2019:"
+`, []string{`<guid isPermaLink="false">tag:example.com,2019:`, `<guid isPermaLink="false">my-p2-guid</guid>`}},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`+this.config)
+		b.WithContent(
+			"p1.md", "---\ntitle: p1\n---\n",
+			"p2.md", "---\ntitle: p2\nguid: my-p2-guid\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/index.xml", this.expected...)
+	}
+
+	// The stable guid must not depend on the URL.
+	guid := func(url string) string {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"
+[services.rss]
+guid = "stable"
+`)
+		b.WithContent("p1.md", "---\ntitle: p1\nurl: "+url+"\n---\n")
+		b.Build(BuildCfg{})
+		content := b.FileContent("public/index.xml")
+		start := strings.Index(content, `<guid isPermaLink="false">`)
+		require.True(t, start != -1, content)
+		return content[start : start+strings.Index(content[start:], "</guid>")]
+	}
+
+	require.Equal(t, guid("/old/p1/"), guid("/new/p1/"))
+}
+
+func TestRSSLimitFromFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+defaultContentLanguage = "en"
+rssLimit = 2
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+`)
+
+	for _, lang := range []string{"en", "nn"} {
+		for i := 1; i <= 5; i++ {
+			b.WithContent(fmt.Sprintf("p%d.%s.md", i, lang), fmt.Sprintf("---\ntitle: p%d\ntags: [news, quiet]\n---\n", i))
+		}
+	}
+	b.WithContent(
+		"tags/news/_index.en.md", "---\ntitle: News\nrss:\n  limit: 4\n---\n",
+		"tags/news/_index.nn.md", "---\ntitle: Nyhende\nrss:\n  limit: 3\n---\n",
+	)
+
+	b.Build(BuildCfg{})
+
+	count := func(filename string, expected int) {
+		content := b.FileContent(filename)
+		if c := strings.Count(content, "<item>"); c != expected {
+			t.Errorf("%s: expected %d items, got %d:\n%s", filename, expected, c, content)
+		}
+	}
+
+	count("public/tags/news/index.xml", 4)
+	count("public/nn/tags/news/index.xml", 3)
+	count("public/tags/quiet/index.xml", 2)
+	count("public/nn/tags/quiet/index.xml", 2)
+	count("public/index.xml", 2)
+}
+
+func TestRSSSkipHoursAndDays(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	logger := loggers.NewLogger(jww.LevelWarn, jww.LevelError, &warnings, ioutil.Discard, false)
+
+	b := newTestSitesBuilder(t).WithLogger(logger).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[services.rss]
+ttl = 60
+skipHours = [0, 7, 24]
+skipDays = ["saturday", "Sunday", "Caturday"]
+`)
+	b.WithContent("p1.md", "---\ntitle: p1\ndate: 2019-02-03T10:20:30Z\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml", `</lastBuildDate>
+    <ttl>60</ttl>
+    <skipHours>
+      <hour>0</hour>
+      <hour>7</hour>
+    </skipHours>
+    <skipDays>
+      <day>Saturday</day>
+      <day>Sunday</day>
+    </skipDays>`)
+
+	content := b.FileContent("public/index.xml")
+	if strings.Contains(content, "<hour>24</hour>") || strings.Contains(content, "Caturday") {
+		t.Errorf("invalid skip values emitted:\n%s", content)
+	}
+	require.Equal(t, uint64(2), logger.WarnCounter.Count())
+	require.Contains(t, warnings.String(), "Invalid RSS skipHours value 24, must be in the range 0-23")
+	require.Contains(t, warnings.String(), `Invalid RSS skipDays value "Caturday", must be a day name, e.g. Saturday`)
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+	b.Build(BuildCfg{})
+
+	content = b.FileContent("public/index.xml")
+	if strings.Contains(content, "<ttl>") || strings.Contains(content, "<skipHours>") || strings.Contains(content, "<skipDays>") {
+		t.Errorf("ttl and skip hints emitted without configuration:\n%s", content)
+	}
+}
+
+func TestRSSLanguageFallback(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+defaultContentLanguage = "en"
+[services.rss.languageCodes]
+en = "en-us"
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[languages.de]
+weight = 3
+languageCode = "de-ch"
+`)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml", "<language>en-us</language>")
+	b.AssertFileContent("public/nn/index.xml", "<language>nn</language>")
+	b.AssertFileContent("public/de/index.xml", "<language>de-ch</language>")
+}
+
+func TestRSSLanguageLimitAndTitle(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "My Site"
+defaultContentLanguage = "en"
+[services.rss]
+limit = 3
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[languages.nn.rss]
+limit = 1
+title = "Nyheiter"
+[languages.de]
+weight = 3
+`)
+	for _, lang := range []string{"en", "nn", "de"} {
+		for i := 1; i <= 4; i++ {
+			b.WithContent(fmt.Sprintf("p%d.%s.md", i, lang), fmt.Sprintf("---\ntitle: p%d\ndate: 2019-01-0%d\n---\n", i, i))
+		}
+	}
+	b.WithContent("news/_index.nn.md", "---\ntitle: News\nrss:\n  title: Siste nytt\n---\n")
+	b.WithContent("sport/_index.nn.md", "---\ntitle: Sport\n---\n")
+	b.Build(BuildCfg{})
+
+	for _, test := range []struct {
+		filename string
+		title    string
+		items    int
+	}{
+		{"public/index.xml", "<title>My Site</title>", 3},
+		{"public/nn/index.xml", "<title>Nyheiter</title>", 1},
+		{"public/de/index.xml", "<title>My Site</title>", 3},
+	} {
+		content := b.FileContent(test.filename)
+		require.Contains(t, content, test.title, test.filename)
+		require.Equal(t, test.items, strings.Count(content, "<item>"), test.filename)
+	}
+
+	// The front matter still wins.
+	b.AssertFileContent("public/nn/news/index.xml", "<title>Siste nytt</title>")
+	// The language title is the home page feed's only.
+	b.AssertFileContent("public/nn/sport/index.xml", "<title>Sport on My Site</title>")
+}
+
+func TestRSSTitleFromFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+title = "My Site"
+`)
+	b.WithTemplatesAdded("_default/list.html", `{{ .Title }}`)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [news, sports]\n---\n",
+		"tags/news/_index.md", "---\ntitle: news\nrss:\n  title: Latest Headlines\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/tags/news/index.xml", "<title>Latest Headlines</title>")
+	b.AssertFileContent("public/tags/news/index.html", "news")
+	b.AssertFileContent("public/tags/sports/index.xml", "<title>sports on My Site</title>")
+}
+
+func TestRSSWhitespace(t *testing.T) {
+	t.Parallel()
+
+	for _, minify := range []bool{false, true} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "http://example.com/"
+title = "My Site"
+[services.rss]
+minify = %t
+`, minify))
+		b.WithContent("p1.md", "---\ntitle: p1\ndate: 2019-02-03T10:20:30Z\n---\n")
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/index.xml")
+		if minify {
+			b.AssertFileContent("public/index.xml", `<channel><title>My Site</title><link>http://example.com/</link>`)
+			require.NotContains(t, content, "\n")
+			continue
+		}
+
+		require.True(t, strings.HasPrefix(content, "<?xml"), content)
+		require.True(t, strings.HasSuffix(content, "</rss>\n"), content)
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			require.NotEmpty(t, strings.TrimSpace(line), content)
+		}
+		b.AssertFileContent("public/index.xml", `<generator>Hugo -- gohugo.io</generator>
+    <language>en</language>
+    <lastBuildDate>Sun, 03 Feb 2019 10:20:30 +0000</lastBuildDate>
+    <atom:link href="http://example.com/index.xml" rel="self" type="application/rss+xml" />
+    <item>
+      <title>p1</title>`)
+	}
+}
+
+func TestRSSDCCreator(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`)
+	b.WithContent(
+		"blog/p1.md", "---\ntitle: p1\nauthor: Jane Doe\n---\n",
+		"blog/p2.md", "---\ntitle: p2\nauthor: [Jane Doe, John Doe]\n---\n",
+		"docs/p3.md", "---\ntitle: p3\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/blog/index.xml",
+		`xmlns:dc="http://purl.org/dc/elements/1.1/"`,
+		"<dc:creator>Jane Doe</dc:creator>",
+		"<dc:creator>Jane Doe, John Doe</dc:creator>",
+	)
+
+	content := b.FileContent("public/docs/index.xml")
+	require.NotContains(t, content, "xmlns:dc")
+	require.NotContains(t, content, "<dc:creator>")
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[author]
+name = "Site Author"
+email = "author@example.com"
+`)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\n---\n",
+		"p2.md", "---\ntitle: p2\nauthor: Jane Doe\n---\n",
+		"p3.md", "---\ntitle: p3\nauthor:\n  name: Dora\n  twitter: dora\n---\n",
+		"p4.md", "---\ntitle: p4\nauthor:\n- name: Tom & Jerry\n- name: Spike\n---\n",
+		"p5.md", "---\ntitle: p5\nauthor:\n- twitter: nameless\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		`xmlns:dc="http://purl.org/dc/elements/1.1/"`,
+		`<author>author@example.com (Site Author)</author>
+      <dc:creator>Site Author</dc:creator>`,
+		"<dc:creator>Jane Doe</dc:creator>",
+		"<dc:creator>Dora</dc:creator>",
+		"<dc:creator>Tom &amp; Jerry, Spike</dc:creator>",
+	)
+	content = b.FileContent("public/index.xml")
+	require.NotContains(t, content, "map[")
+	// p1 and p5 fall back to the site author.
+	require.Equal(t, 2, strings.Count(content, "<dc:creator>Site Author</dc:creator>"))
+
+	// Author maps without names give no creator, and no dc namespace.
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+`)
+	b.WithContent("p1.md", "---\ntitle: p1\nauthor:\n- twitter: nameless\n---\n")
+	b.Build(BuildCfg{})
+
+	content = b.FileContent("public/index.xml")
+	require.NotContains(t, content, "xmlns:dc")
+	require.NotContains(t, content, "<dc:creator>")
+}
+
+func TestRSSItemPartial(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent("p1.md", "---\ntitle: p1\n---\nSome <em>content</em> with an ![image](/img/a.png).\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml", "<description>Some content with an .</description>")
+
+	b = newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent("p1.md", "---\ntitle: p1\n---\nSome <em>content</em> with an ![image](/img/a.png).\n")
+	b.WithTemplatesAdded("partials/rss-item.html", `{{ .Title }}: {{ .Content }}`)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml", `<description>p1: &lt;p&gt;Some &lt;em&gt;content&lt;/em&gt; with an &lt;img`)
+}
+
+func TestRSSSelfLink(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[mediaTypes."application/atom+xml"]
+suffixes = ["xml"]
+[outputFormats.Feed]
+mediaType = "application/rss+xml"
+baseName = "feed"
+[outputFormats.Atom]
+mediaType = "application/atom+xml"
+baseName = "atom"
+[outputs]
+home = ["HTML", "RSS", "Feed", "Atom"]
+`)
+	b.WithTemplatesAdded(
+		"index.feed.xml", `{{ template "_internal/_default/rss.xml" . }}`,
+		"index.atom.xml", `Atom`,
+	)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		`<atom:link href="http://example.com/index.xml" rel="self" type="application/rss+xml" />`,
+		`<atom:link href="http://example.com/feed.xml" rel="alternate" type="application/rss+xml" />`,
+		`<atom:link href="http://example.com/atom.xml" rel="alternate" type="application/atom+xml" />`,
+	)
+	b.AssertFileContent("public/feed.xml",
+		`<atom:link href="http://example.com/feed.xml" rel="self" type="application/rss+xml" />`,
+		`<atom:link href="http://example.com/index.xml" rel="alternate" type="application/rss+xml" />`,
+		`<atom:link href="http://example.com/atom.xml" rel="alternate" type="application/atom+xml" />`,
+	)
+	require.Equal(t, 1, strings.Count(b.FileContent("public/feed.xml"), `rel="self"`))
+}
+
+func TestRSSIncludeDraftsFutureExpired(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+buildDrafts = true
+buildFuture = true
+buildExpired = true
+[services.rss]
+includeFuture = false
+includeExpired = false
+limit = 2
+`)
+	b.WithContent(
+		"draft.md", "---\ntitle: draft\ndraft: true\ndate: 2019-01-04\n---\n",
+		"future.md", "---\ntitle: future\npublishDate: 2099-01-01\n---\n",
+		"expired.md", "---\ntitle: expired\ndate: 2019-01-03\nexpiryDate: 2000-01-01\n---\n",
+		"current.md", "---\ntitle: current\ndate: 2019-01-02\n---\n",
+		"old.md", "---\ntitle: old\ndate: 2019-01-01\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/index.xml")
+	require.Contains(t, content, "<link>http://example.com/draft/</link>")
+	require.Contains(t, content, "<link>http://example.com/current/</link>")
+	require.NotContains(t, content, "/future/")
+	require.NotContains(t, content, "/expired/")
+	require.NotContains(t, content, "/old/")
+
+	// The sitemap isn't affected.
+	b.AssertFileContent("public/sitemap.xml", "<loc>http://example.com/future/</loc>", "<loc>http://example.com/expired/</loc>")
+}
+
+func TestRSSFullContent(t *testing.T) {
+	t.Parallel()
+
+	config := `
+baseURL = "https://example.com/blog/"
+[services.rss]
+fullContent = true
+`
+	content := `---
+title: post
+---
+
+<img src="sunset.jpg" srcset="sunset.jpg 1x, /images/sunset-2x.jpg 2x" alt="Sunset">
+
+[About](../about/), [CDN](//cdn.example.org/logo.png) and [Elsewhere](https://example.org/).
+`
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", config)
+	b.WithContent("post.md", content)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		`xmlns:content="http://purl.org/rss/1.0/modules/content/"`,
+		`<content:encoded>&lt;p&gt;&lt;img src=&#34;https://example.com/blog/post/sunset.jpg&#34; srcset=&#34;https://example.com/blog/post/sunset.jpg 1x, https://example.com/images/sunset-2x.jpg 2x&#34; alt=&#34;Sunset&#34;&gt;&lt;/p&gt;`,
+		`&lt;a href=&#34;https://example.com/blog/about/&#34;&gt;About&lt;/a&gt;`,
+		`&lt;a href=&#34;https://cdn.example.org/logo.png&#34;&gt;CDN&lt;/a&gt;`,
+		`&lt;a href=&#34;https://example.org/&#34;&gt;Elsewhere&lt;/a&gt;`,
+	)
+
+	b = newTestSitesBuilder(t).WithConfigFile("toml", config+"absolutizeURLs = false\n")
+	b.WithContent("post.md", content)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/index.xml",
+		`<content:encoded>&lt;p&gt;&lt;img src=&#34;sunset.jpg&#34; srcset=&#34;sunset.jpg 1x, /images/sunset-2x.jpg 2x&#34;`,
+		`&lt;a href=&#34;//cdn.example.org/logo.png&#34;&gt;CDN&lt;/a&gt;`,
+	)
+
+	// No full content by default.
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "https://example.com/blog/"`)
+	b.WithContent("post.md", content)
+	b.Build(BuildCfg{})
+
+	require.NotContains(t, b.FileContent("public/index.xml"), "content:encoded")
+}
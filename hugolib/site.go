@@ -32,6 +32,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/maps"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 
 	"github.com/gohugoio/hugo/common/text"
@@ -94,6 +95,9 @@ type Site struct {
 
 	Taxonomies TaxonomyList
 
+	// Opt-in term intersections, e.g. tags/go+web, keyed by plural.
+	taxonomyIntersections TaxonomyList
+
 	taxonomyNodes *taxonomyNodeInfos
 
 	Sections Taxonomy
@@ -162,11 +166,12 @@ type Site struct {
 }
 
 type siteConfigHolder struct {
-	sitemap          config.Sitemap
-	taxonomiesConfig map[string]string
-	timeout          time.Duration
-	hasCJKLanguage   bool
-	enableEmoji      bool
+	sitemap                     config.Sitemap
+	taxonomiesConfig            map[string]string
+	taxonomyIntersectionsConfig map[string][]string
+	timeout                     time.Duration
+	hasCJKLanguage              bool
+	enableEmoji                 bool
 }
 
 // Lazily loaded site dependencies.
@@ -412,6 +417,11 @@ func newSite(cfg deps.DepsCfg) (*Site, error) {
 
 	taxonomies := cfg.Language.GetStringMapString("taxonomies")
 
+	taxonomyIntersections := make(map[string][]string)
+	for plural, v := range cfg.Language.GetStringMap("taxonomyIntersections") {
+		taxonomyIntersections[plural] = cast.ToStringSlice(v)
+	}
+
 	var relatedContentConfig related.Config
 
 	if cfg.Language.IsSet("related") {
@@ -434,11 +444,12 @@ func newSite(cfg deps.DepsCfg) (*Site, error) {
 	}
 
 	siteConfig := siteConfigHolder{
-		sitemap:          config.DecodeSitemap(config.Sitemap{Priority: -1, Filename: "sitemap.xml"}, cfg.Language.GetStringMap("sitemap")),
-		taxonomiesConfig: taxonomies,
-		timeout:          time.Duration(cfg.Language.GetInt("timeout")) * time.Millisecond,
-		hasCJKLanguage:   cfg.Language.GetBool("hasCJKLanguage"),
-		enableEmoji:      cfg.Language.Cfg.GetBool("enableEmoji"),
+		sitemap:                     config.DecodeSitemap(config.Sitemap{Priority: -1, Filename: "sitemap.xml", ExcludeNoindex: true, IncludeDrafts: true, IncludeFuture: true, IncludeExpired: true}, cfg.Language.GetStringMap("sitemap")),
+		taxonomiesConfig:            taxonomies,
+		taxonomyIntersectionsConfig: taxonomyIntersections,
+		timeout:                     time.Duration(cfg.Language.GetInt("timeout")) * time.Millisecond,
+		hasCJKLanguage:              cfg.Language.GetBool("hasCJKLanguage"),
+		enableEmoji:                 cfg.Language.Cfg.GetBool("enableEmoji"),
 	}
 
 	s := &Site{
@@ -607,6 +618,30 @@ func (s *SiteInfo) Taxonomies() interface{} {
 	return s.s.Taxonomies
 }
 
+// OrphanTerms returns the terms that have a term page, e.g. from a content
+// file, but no pages assigned, ordered by taxonomy and term.
+func (s *SiteInfo) OrphanTerms() []OrphanTerm {
+	var terms []OrphanTerm
+	for _, p := range s.s.findWorkPagesByKind(page.KindTaxonomy) {
+		info := p.getTaxonomyNodeInfo()
+		if info == nil || info.intersection {
+			continue
+		}
+		if len(s.s.Taxonomies[info.plural][info.termKey]) == 0 {
+			terms = append(terms, OrphanTerm{Plural: info.plural, Term: info.termKey})
+		}
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Plural != terms[j].Plural {
+			return terms[i].Plural < terms[j].Plural
+		}
+		return terms[i].Term < terms[j].Term
+	})
+
+	return terms
+}
+
 func (s *SiteInfo) Params() map[string]interface{} {
 	return s.s.Language().Params()
 }
@@ -1261,9 +1296,17 @@ func (s *Site) initializeSiteInfo() error {
 		}
 	}
 
+	// The author profiles, keyed by the identifiers used in the authors
+	// front matter.
+	var authors page.AuthorList
+	if err := mapstructure.WeakDecode(lang.GetStringMap("authors"), &authors); err != nil {
+		return errors.Wrap(err, "failed to decode authors")
+	}
+
 	s.Info = SiteInfo{
 		title:                          lang.GetString("title"),
 		Author:                         lang.GetStringMap("author"),
+		Authors:                        authors,
 		Social:                         lang.GetStringMapString("social"),
 		LanguageCode:                   lang.GetString("languageCode"),
 		Copyright:                      lang.GetString("copyright"),
@@ -1506,6 +1549,7 @@ func (s *Site) assembleTaxonomies() error {
 
 			n := s.taxonomyNodes.GetOrCreate(plural, term)
 			n.parent = parent
+			n.setTermFrom(term, p)
 
 			w := page.NewWeightedPage(weight, p, n.owner)
 
@@ -1543,9 +1587,76 @@ func (s *Site) assembleTaxonomies() error {
 		}
 	}
 
+	s.assembleTaxonomyIntersections()
+
 	return nil
 }
 
+// assembleTaxonomyIntersections creates the term intersections configured in
+// taxonomyIntersections, e.g. tags = ["go+web"]. These get their own taxonomy
+// nodes and pages, but are kept out of s.Taxonomies. Intersections without any
+// pages are skipped.
+func (s *Site) assembleTaxonomyIntersections() {
+	s.taxonomyIntersections = make(TaxonomyList)
+
+	for plural, combinations := range s.siteCfg.taxonomyIntersectionsConfig {
+		taxonomy, found := s.Taxonomies[plural]
+		if !found {
+			s.Log.WARN.Printf("Intersections configured for unknown taxonomy %q", plural)
+			continue
+		}
+
+		parent := s.taxonomyNodes.Get(plural)
+		intersections := make(Taxonomy)
+
+		for _, combination := range combinations {
+			var terms, keys []string
+			for _, term := range strings.Split(combination, "+") {
+				term = strings.TrimSpace(term)
+				if term == "" {
+					continue
+				}
+				terms = append(terms, term)
+				keys = append(keys, s.getTaxonomyKey(term))
+			}
+
+			if len(keys) < 2 {
+				s.Log.WARN.Printf("Invalid intersection %q for taxonomy %q: needs at least two terms", combination, plural)
+				continue
+			}
+
+			pages := taxonomy.intersect(keys...)
+			if len(pages) == 0 {
+				continue
+			}
+
+			key := strings.Join(keys, "+")
+			n := s.taxonomyNodes.GetOrCreate(plural, key)
+			n.parent = parent
+			n.term = strings.Join(terms, " + ")
+			n.intersection = true
+
+			for i, w := range pages {
+				pages[i] = page.NewWeightedPage(w.Weight, w.Page, n.owner)
+				n.UpdateFromPage(w.Page)
+			}
+
+			intersections[key] = pages
+		}
+
+		s.taxonomyIntersections[plural] = intersections
+	}
+}
+
+// taxonomyTermPages returns the weighted pages for the given term key, which
+// may also be one of the configured term intersections.
+func (s *Site) taxonomyTermPages(plural, termKey string) page.WeightedPages {
+	if pages, found := s.Taxonomies[plural][termKey]; found {
+		return pages
+	}
+	return s.taxonomyIntersections[plural][termKey]
+}
+
 // Prepare site for a new full build.
 func (s *Site) resetBuildState() {
 	s.relatedDocsHandler = s.relatedDocsHandler.Clone()
@@ -1625,6 +1736,7 @@ func (s *Site) renderAndWriteXML(statCounter *uint64, name string, targetPath st
 		// we currently only use the MIME type.
 		OutputFormat: output.RSSFormat,
 		AbsURLPath:   path,
+		Minify:       s.siteCfg.sitemap.Minify,
 	}
 
 	return s.publisher.Publish(pd)
@@ -1670,6 +1782,7 @@ func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath s
 	if isRSS {
 		// Always canonify URLs in RSS
 		pd.AbsURLPath = path
+		pd.Minify = s.siteConfigConfig.Services.RSS.Minify
 	} else if isHTML {
 		if s.Info.relativeURLs || s.Info.canonifyURLs {
 			pd.AbsURLPath = path
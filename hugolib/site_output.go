@@ -61,6 +61,7 @@ func createSiteOutputFormats(allFormats output.Formats, cfg config.Provider) (ma
 	seen := make(map[string]bool)
 
 	for k, v := range outputs {
+		k = canonicalKind(k)
 		var formats output.Formats
 		vals := cast.ToStringSlice(v)
 		for _, format := range vals {
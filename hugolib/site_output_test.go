@@ -553,3 +553,38 @@ Output Formats: {{ len .OutputFormats }};{{ range .OutputFormats }}{{ .Name }};{
 	)
 
 }
+
+func TestTaxonomyFeedOutputFormats(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{"Default", "", "html,rss"},
+		{"Feeds disabled", `
+[outputs]
+taxonomy = ["html"]
+taxonomyTerm = ["html"]
+`, "html"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := newTestSitesBuilder(t)
+			b.WithConfigFile("toml", `baseURL = "http://example.com/"
+`+test.config)
+			b.WithTemplatesAdded(
+				"_default/terms.html", `{{ range .OutputFormats }}{{ .Name | lower }},{{ end }}`,
+				"_default/taxonomy.html", `{{ range .OutputFormats }}{{ .Name | lower }},{{ end }}`,
+			)
+			b.WithContent("p1.md", "---\ntitle: P1\ntags: [hugo]\n---")
+
+			b.Build(BuildCfg{})
+
+			b.AssertFileContent("public/tags/index.html", test.expect+",")
+			b.AssertFileContent("public/tags/hugo/index.html", test.expect+",")
+			require.Equal(t, test.expect == "html,rss", b.CheckExists("public/tags/hugo/index.xml"))
+			require.Equal(t, test.expect == "html,rss", b.CheckExists("public/tags/index.xml"))
+		})
+	}
+}
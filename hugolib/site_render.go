@@ -24,8 +24,11 @@ import (
 	"github.com/gohugoio/hugo/output"
 	"github.com/pkg/errors"
 
+	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/resources/page"
 	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/cast"
 )
 
 type siteRenderContext struct {
@@ -255,11 +258,73 @@ func (s *Site) renderSitemap() error {
 		return errors.New("failed to create targetPath for sitemap")
 	}
 
+	for _, name := range s.siteCfg.sitemap.OutputFormats {
+		if _, found := s.outputFormatsConfig.GetByName(name); !found {
+			s.Log.WARN.Printf("Unknown output format %q in sitemap.outputFormats", name)
+		}
+	}
+
 	smLayouts := []string{"sitemap.xml", "_default/sitemap.xml", "_internal/_default/sitemap.xml"}
 
 	return s.renderAndWriteXML(&s.PathSpec.ProcessingStats.Sitemaps, "sitemap", targetPath, p, smLayouts...)
 }
 
+// sitemapPages returns the pages to list in the sitemap, filtered on the
+// page kinds set in sitemap.kinds, if any. Pages with a noindex robots
+// directive are left out unless sitemap.excludeNoindex is false, and
+// draft, future and expired pages unless the sitemap includes them.
+func (s *Site) sitemapPages() page.Pages {
+	cfg := s.siteCfg.sitemap
+	kinds := cfg.Kinds
+	excludeNoindex := cfg.ExcludeNoindex
+	if len(kinds) == 0 && !excludeNoindex && cfg.IncludeDrafts && cfg.IncludeFuture && cfg.IncludeExpired {
+		return s.Pages()
+	}
+
+	var include map[string]bool
+	if len(kinds) > 0 {
+		include = make(map[string]bool)
+		for _, kind := range kinds {
+			kind = canonicalKind(kind)
+			if !helpers.InStringArray(allKindsInPages, kind) {
+				s.Log.WARN.Printf("Unknown page kind %q in sitemap.kinds", kind)
+			}
+			include[kind] = true
+		}
+	}
+
+	var pages page.Pages
+	for _, p := range s.Pages() {
+		if include != nil && !include[p.Kind()] {
+			continue
+		}
+		if excludeNoindex && isNoindex(p) {
+			continue
+		}
+		if (!cfg.IncludeDrafts && p.Draft()) || (!cfg.IncludeFuture && resource.IsFuture(p)) || (!cfg.IncludeExpired && resource.IsExpired(p)) {
+			continue
+		}
+		pages = append(pages, p)
+	}
+
+	return pages
+}
+
+// isNoindex reports whether the page's robots front matter tells search
+// engines not to index it, i.e. sets robots.noindex. The same map holds
+// robots.disallow, used by the robots.txt template.
+func isNoindex(p page.Page) bool {
+	robots, found := p.Params()["robots"]
+	if !found {
+		return false
+	}
+	m, err := cast.ToStringMapE(robots)
+	if err != nil {
+		return false
+	}
+	return cast.ToBool(m["noindex"])
+}
+
 func (s *Site) renderRobotsTXT() error {
 	if !s.isEnabled(kindRobotsTXT) {
 		return nil
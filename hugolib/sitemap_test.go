@@ -14,13 +14,18 @@
 package hugolib
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"reflect"
 
+	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/deps"
 	"github.com/gohugoio/hugo/tpl"
+	jww "github.com/spf13/jwalterweatherman"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,11 +91,12 @@ func doTestSitemapOutput(t *testing.T, internal bool) {
 
 func TestParseSitemap(t *testing.T) {
 	t.Parallel()
-	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3"}
+	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3", DateFormat: "2006-01-02"}
 	input := map[string]interface{}{
 		"changefreq": "3",
 		"priority":   3.0,
 		"filename":   "doo.xml",
+		"dateformat": "2006-01-02",
 		"unknown":    "ignore",
 	}
 	result := config.DecodeSitemap(config.Sitemap{}, input)
@@ -119,3 +125,267 @@ outputs: [ "html", "amp" ]
 	// Should link to the HTML version.
 	b.AssertFileContent("public/sitemap.xml", " <loc>http://example.com/blog/html-amp/</loc>")
 }
+
+func TestSitemapOutputFormatsConfig(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[sitemap]
+outputFormats = ["HTML", "amp"]
+`)
+	b.WithContent(
+		"blog/html-amp.md", "---\ntitle: HTML and AMP\noutputs: [\"html\", \"amp\"]\n---\n",
+		"blog/html.md", "---\ntitle: HTML\noutputs: [\"html\"]\n---\n",
+		"blog/amp-only.md", "---\ntitle: AMP only\noutputs: [\"html\", \"amp\"]\nsitemap:\n  outputFormats: [\"amp\"]\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/sitemap.xml")
+	for _, loc := range []string{
+		"http://example.com/blog/html-amp/",
+		"http://example.com/amp/blog/html-amp/",
+		"http://example.com/blog/html/",
+		"http://example.com/amp/blog/amp-only/",
+	} {
+		require.Equal(t, 1, strings.Count(content, "<loc>"+loc+"</loc>"), loc)
+	}
+	require.NotContains(t, content, "<loc>http://example.com/amp/blog/html/</loc>")
+	require.NotContains(t, content, "<loc>http://example.com/blog/amp-only/</loc>")
+}
+
+func TestSitemapDateFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, this := range []struct {
+		config   string
+		expected string
+	}{
+		{``, "<lastmod>2019-02-03T10:20:30+00:00</lastmod>"},
+		{`
+[sitemap]
+dateFormat = "2006-01-02"
+`, "<lastmod>2019-02-03</lastmod>"},
+		{`
+[sitemap]
+dateFormat = "2 January 2006"
+`, "<lastmod>3 février 2019</lastmod>"},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`+this.config)
+		b.WithSourceFile("i18n/en.toml", "[February]\nother = \"février\"\n")
+		b.WithContent("p1.md", "---\ntitle: p1\nlastmod: 2019-02-03T10:20:30Z\n---\n")
+		b.Build(BuildCfg{})
+
+		b.AssertFileContent("public/sitemap.xml", this.expected)
+	}
+}
+
+func TestSitemapHreflangAliases(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.pt]
+weight = 2
+hreflangAliases = ["pt-br", "pt-pt", "PT"]
+`)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n", "p1.pt.md", "---\ntitle: p1 pt\n---\n")
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/en/sitemap.xml")
+	for _, hreflang := range []string{"en", "pt", "pt-br", "pt-pt"} {
+		// The home page and p1, each linking to both translations.
+		require.Equal(t, 4, strings.Count(content, fmt.Sprintf(`hreflang="%s"`, hreflang)), hreflang)
+	}
+	require.NotContains(t, content, `hreflang="PT"`)
+	b.AssertFileContent("public/en/sitemap.xml", `hreflang="pt-br"
+                href="http://example.com/pt/p1/"`)
+}
+
+func TestSitemapKinds(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[sitemap]
+kinds = ["page", "Section", "home"]
+`)
+	b.WithContent(
+		"blog/_index.md", "---\ntitle: Blog\n---\n",
+		"blog/p1.md", "---\ntitle: p1\ntags: [\"hugo\"]\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	content := b.FileContent("public/sitemap.xml")
+	for _, loc := range []string{"http://example.com/", "http://example.com/blog/", "http://example.com/blog/p1/"} {
+		require.Contains(t, content, "<loc>"+loc+"</loc>")
+	}
+	require.NotContains(t, content, "/tags/")
+}
+
+func TestSitemapExcludeNoindex(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config  string
+		exclude bool
+	}{
+		{"", true},
+		{"[sitemap]\nexcludeNoindex = false", false},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+enableRobotsTXT = true
+`+test.config)
+		b.WithTemplates("_default/single.html", "{{ .Title }}")
+		b.WithContent(
+			"p1.md", "---\ntitle: p1\nrobots:\n  noIndex: true\n---\n",
+			"p2.md", "---\ntitle: p2\nrobots:\n  noindex: false\n---\n",
+			"p3.md", "---\ntitle: p3\nrobots:\n  noindex: true\n  disallow: true\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/sitemap.xml")
+		require.Contains(t, content, "<loc>http://example.com/p2/</loc>")
+		if test.exclude {
+			require.NotContains(t, content, "/p1/")
+			require.NotContains(t, content, "/p3/")
+		} else {
+			require.Contains(t, content, "<loc>http://example.com/p1/</loc>")
+			require.Contains(t, content, "<loc>http://example.com/p3/</loc>")
+		}
+
+		// The robots map is shared with robots.txt.
+		robots := b.FileContent("public/robots.txt")
+		require.Contains(t, robots, "Disallow: /p3/")
+		require.NotContains(t, robots, "/p1/")
+	}
+}
+
+func TestSitemapIncludeDraftsFutureExpired(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		config   string
+		included []string
+		excluded []string
+	}{
+		{"", []string{"draft", "future", "expired", "current"}, nil},
+		{"[sitemap]\nincludeFuture = false", []string{"draft", "expired", "current"}, []string{"future"}},
+		{"[sitemap]\nincludeDrafts = false\nincludeExpired = false", []string{"future", "current"}, []string{"draft", "expired"}},
+	} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+buildDrafts = true
+buildFuture = true
+buildExpired = true
+`+test.config)
+		b.WithContent(
+			"draft.md", "---\ntitle: draft\ndraft: true\n---\n",
+			"future.md", "---\ntitle: future\npublishDate: 2099-01-01\n---\n",
+			"expired.md", "---\ntitle: expired\nexpiryDate: 2000-01-01\n---\n",
+			"current.md", "---\ntitle: current\n---\n",
+		)
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/sitemap.xml")
+		for _, name := range test.included {
+			require.Contains(t, content, "<loc>http://example.com/"+name+"/</loc>", test.config)
+		}
+		for _, name := range test.excluded {
+			require.NotContains(t, content, "/"+name+"/", test.config)
+		}
+	}
+}
+
+func TestSitemapWhitespace(t *testing.T) {
+	t.Parallel()
+
+	for _, minify := range []bool{false, true} {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", fmt.Sprintf(`
+baseURL = "http://example.com/"
+[sitemap]
+minify = %t
+`, minify))
+		b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+		b.Build(BuildCfg{})
+
+		content := b.FileContent("public/sitemap.xml")
+		if minify {
+			b.AssertFileContent("public/sitemap.xml", `<url><loc>http://example.com/p1/</loc></url>`)
+			require.NotContains(t, content, "\n")
+			continue
+		}
+
+		require.True(t, strings.HasPrefix(content, "<?xml"), content)
+		require.True(t, strings.HasSuffix(content, "</urlset>\n"), content)
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			require.NotEmpty(t, strings.TrimSpace(line), content)
+		}
+		b.AssertFileContent("public/sitemap.xml", `
+  <url>
+    <loc>http://example.com/p1/</loc>
+  </url>`)
+	}
+}
+
+func TestSitemapLastmodSource(t *testing.T) {
+	t.Parallel()
+
+	content := func(source string) string {
+		b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[sitemap]
+lastmodSource = "`+source+`"
+`)
+		b.WithContent(
+			"p1.md", "---\ntitle: p1\ndate: 2019-01-01\npublishDate: 2019-02-02\nlastmod: 2019-03-03\n---\n",
+			"p2.md", "---\ntitle: p2\ndate: 2019-01-01\npublishDate: 2019-02-02\nlastmod: 2019-03-03\nsitemap:\n  lastmodSource: publishDate\n---\n",
+		)
+		b.Build(BuildCfg{})
+		return b.FileContent("public/sitemap.xml")
+	}
+
+	for _, test := range []struct {
+		source   string
+		p1Date   string
+		p2Date   string
+		expectP1 bool
+	}{
+		{"", "2019-03-03", "2019-02-02", true},
+		{"lastmod", "2019-03-03", "2019-02-02", true},
+		{"date", "2019-01-01", "2019-02-02", true},
+		{"publishDate", "2019-02-02", "2019-02-02", true},
+		// Git info isn't enabled.
+		{"git", "", "2019-02-02", false},
+	} {
+		c := content(test.source)
+		p2 := "<loc>http://example.com/p2/</loc>\n    <lastmod>" + test.p2Date
+		require.Contains(t, c, p2, test.source)
+		p1 := "<loc>http://example.com/p1/</loc>\n    <lastmod>" + test.p1Date
+		if test.expectP1 {
+			require.Contains(t, c, p1, test.source)
+		} else {
+			require.Contains(t, c, "<loc>http://example.com/p1/</loc>\n  </url>", test.source)
+		}
+	}
+}
+
+func TestSitemapLastmodSourceInvalid(t *testing.T) {
+	t.Parallel()
+
+	logger := loggers.NewLogger(jww.LevelError, jww.LevelError, ioutil.Discard, ioutil.Discard, true)
+	b := newTestSitesBuilder(t).WithLogger(logger).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[sitemap]
+lastmodSource = "modified"
+`)
+	b.WithContent("p1.md", "---\ntitle: p1\n---\n")
+
+	require.Error(t, b.BuildE(BuildCfg{}))
+	require.Contains(t, logger.Errors(), `sitemap.lastmodSource must be one of lastmod, date, publishDate or git, got "modified"`)
+}
@@ -17,6 +17,10 @@ import (
 	"fmt"
 	"path"
 	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gohugoio/hugo/compare"
 
@@ -32,6 +36,221 @@ func (tl TaxonomyList) String() string {
 	return fmt.Sprintf("TaxonomyList(%d)", len(tl))
 }
 
+// TermActivity is a taxonomy term with the date of its most recent page.
+// See TaxonomyList.NewestTerms.
+type TermActivity struct {
+	// The taxonomy, e.g. "tags".
+	Plural string
+
+	// The term key as used in the taxonomy.
+	Term string
+
+	// The number of pages assigned to the term.
+	Count int
+
+	// The date of the most recent page assigned to the term.
+	Date time.Time
+
+	// The permalink of the term page, empty if not rendered.
+	Permalink string
+}
+
+// NewestTerms returns the terms of all taxonomies, newest first, ordered by
+// the date of the most recent page assigned to each term.
+// At most limit terms are returned; all of them if limit is less than 1.
+func (tl TaxonomyList) NewestTerms(limit int) []TermActivity {
+	var terms []TermActivity
+	for plural, taxonomy := range tl {
+		for term, pages := range taxonomy {
+			if len(pages) == 0 {
+				continue
+			}
+			ta := TermActivity{Plural: plural, Term: term, Count: len(pages)}
+			for _, p := range pages {
+				if p.Date().After(ta.Date) {
+					ta.Date = p.Date()
+				}
+			}
+			if owner := pages.Page(); owner != nil {
+				ta.Permalink = owner.Permalink()
+			}
+			terms = append(terms, ta)
+		}
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		ti, tj := terms[i], terms[j]
+		if !ti.Date.Equal(tj.Date) {
+			return ti.Date.After(tj.Date)
+		}
+		if ti.Count != tj.Count {
+			return ti.Count > tj.Count
+		}
+		if ti.Plural != tj.Plural {
+			return ti.Plural < tj.Plural
+		}
+		return ti.Term < tj.Term
+	})
+
+	if limit > 0 && len(terms) > limit {
+		terms = terms[:limit]
+	}
+
+	return terms
+}
+
+// Permalink returns the permalink of the term page for the given taxonomy
+// and term, empty if either is unknown. See Taxonomy.Permalink.
+func (tl TaxonomyList) Permalink(plural, term string) string {
+	return tl[plural].Permalink(term)
+}
+
+// Tree returns the terms of the given taxonomy as a tree for navigation,
+// empty if the taxonomy is unknown. See Taxonomy.Tree.
+func (tl TaxonomyList) Tree(plural, order string) ([]*TaxonomyNode, error) {
+	return tl[plural].Tree(order)
+}
+
+// RelatedByTerms returns the pages sharing at least minOverlap terms with p,
+// counted across all taxonomies. The pages are ordered by the number of
+// shared terms, descending, then in the default page order. p itself is
+// never included.
+// At most limit pages are returned; all of them if limit is less than 1.
+func (tl TaxonomyList) RelatedByTerms(p page.Page, limit, minOverlap int) page.Pages {
+	if minOverlap < 1 {
+		minOverlap = 1
+	}
+
+	overlap := make(map[page.Page]int)
+	for _, taxonomy := range tl {
+		for _, pages := range taxonomy {
+			if !containsPage(pages, p) {
+				continue
+			}
+			for _, w := range pages {
+				if w.Page != p {
+					overlap[w.Page]++
+				}
+			}
+		}
+	}
+
+	var related page.Pages
+	for rp, count := range overlap {
+		if count >= minOverlap {
+			related = append(related, rp)
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		ci, cj := overlap[related[i]], overlap[related[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return page.DefaultPageSort(related[i], related[j])
+	})
+
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+
+	return related
+}
+
+// PageTaxonomy is a taxonomy with the terms assigned to a page.
+// See TaxonomyList.TermsOf.
+type PageTaxonomy struct {
+	// The taxonomy, e.g. "tags".
+	Plural string
+
+	// The page's terms, in front matter order.
+	Terms []PageTerm
+}
+
+// PageTerm is a taxonomy term assigned to a page.
+type PageTerm struct {
+	// The term as written in the front matter, e.g. "Hugo Tips". If pages
+	// spell it differently, the spelling of the first page in the default
+	// sort order is used.
+	Name string
+
+	// The term key as used in the taxonomy, e.g. "hugo-tips".
+	Key string
+
+	// The permalink of the term page, empty if it isn't rendered, e.g.
+	// because the taxonomy Kind is disabled.
+	Permalink string
+
+	// Whether the term has a rendered term page. Use it to skip or flag the
+	// terms without one, e.g. with where .Terms "HasPage" true.
+	HasPage bool
+}
+
+// TermsOf returns the terms p is assigned to, grouped by taxonomy and
+// ordered by the taxonomy name. The names and keys are resolved the same way
+// as when building the taxonomies, so templates don't need to guess the term
+// URLs from the front matter values. Terms without a rendered term page are
+// kept with an empty Permalink and HasPage set to false.
+func (tl TaxonomyList) TermsOf(p page.Page) []PageTaxonomy {
+	ps, ok := mustUnwrapPage(p).(*pageState)
+	if !ok {
+		return nil
+	}
+
+	plurals := make([]string, 0, len(tl))
+	for plural := range tl {
+		plurals = append(plurals, plural)
+	}
+	sort.Strings(plurals)
+
+	var taxonomies []PageTaxonomy
+	for _, plural := range plurals {
+		var names []string
+		switch v := getParam(ps, plural, false).(type) {
+		case []string:
+			names = v
+		case string:
+			names = []string{v}
+		}
+
+		pt := PageTaxonomy{Plural: plural}
+		seen := make(map[string]bool)
+		for _, name := range names {
+			key := ps.s.getTaxonomyKey(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			term := PageTerm{Name: name, Key: key}
+			if n := ps.s.taxonomyNodes.Get(plural, key); n != nil {
+				term.Name = n.term
+				if n.owner != nil && n.owner.Page != nil {
+					term.Permalink = n.owner.Page.Permalink()
+					term.HasPage = true
+				}
+			}
+			pt.Terms = append(pt.Terms, term)
+		}
+
+		if len(pt.Terms) > 0 {
+			taxonomies = append(taxonomies, pt)
+		}
+	}
+
+	return taxonomies
+}
+
+// OrphanTerm is a taxonomy term without any pages assigned.
+// See SiteInfo.OrphanTerms.
+type OrphanTerm struct {
+	// The taxonomy, e.g. "tags".
+	Plural string
+
+	// The term key as used in the taxonomy.
+	Term string
+}
+
 // A Taxonomy is a map of keywords to a list of pages.
 // For example
 //    TagTaxonomy['technology'] = page.WeightedPages
@@ -57,6 +276,311 @@ func (i Taxonomy) Get(key string) page.WeightedPages {
 // Count the weighted pages for the given key.
 func (i Taxonomy) Count(key string) int { return len(i[key]) }
 
+// CountByKind counts the weighted pages of the given kind, e.g. "page" or
+// "section", for the given key.
+func (i Taxonomy) CountByKind(key, kind string) int {
+	count := 0
+	for _, w := range i[key] {
+		if w.Page.Kind() == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Counts returns the number of pages per term, keyed by the term key as
+// stored in the taxonomy, e.g. "hugo-tips".
+func (i Taxonomy) Counts() map[string]int {
+	counts := make(map[string]int, len(i))
+	for k, v := range i {
+		counts[k] = len(v)
+	}
+	return counts
+}
+
+// CountsByName is like Counts, but keyed by the term as written in the front
+// matter, e.g. "Hugo Tips", or the term key if that isn't known.
+func (i Taxonomy) CountsByName() map[string]int {
+	counts := make(map[string]int, len(i))
+	for k, v := range i {
+		name := termOf(v)
+		if name == "" {
+			name = k
+		}
+		counts[name] += len(v)
+	}
+	return counts
+}
+
+// CountsWithin is like Counts, but only counts the pages that are also in
+// base, e.g. to show the number of pages per term within the current
+// selection of a faceted navigation. Pages are compared by identity. Terms
+// without pages in base are counted as 0.
+func (i Taxonomy) CountsWithin(base page.Pages) map[string]int {
+	inBase := make(map[page.Page]bool, len(base))
+	for _, p := range base {
+		inBase[p] = true
+	}
+
+	counts := make(map[string]int, len(i))
+	for k, v := range i {
+		count := 0
+		for _, w := range v {
+			if inBase[w.Page] {
+				count++
+			}
+		}
+		counts[k] = count
+	}
+	return counts
+}
+
+// Contains reports whether p is assigned to the given key. Pages are
+// compared by identity, so pages sharing a title are told apart.
+func (i Taxonomy) Contains(key string, p page.Page) bool {
+	return containsPage(i[key], p)
+}
+
+// WeightOf returns the weight p has in the given key, as set in the page's
+// taxonomy weight front matter (e.g. tags_weight). It returns 0 if p is not
+// assigned to key, which is ambiguous with an explicit weight of 0; use
+// Contains to tell them apart.
+func (i Taxonomy) WeightOf(key string, p page.Page) int {
+	for _, w := range i[key] {
+		if w.Page == p {
+			return w.Weight
+		}
+	}
+	return 0
+}
+
+// LatestPerTerm returns, for every term in this taxonomy, the n most recent pages
+// ordered by date descending. Terms with fewer than n pages get all of them.
+func (i Taxonomy) LatestPerTerm(n int) map[string]page.Pages {
+	if n < 0 {
+		n = 0
+	}
+	latest := make(map[string]page.Pages, len(i))
+	for k, v := range i {
+		latest[k] = v.Pages().ByDate().Reverse().Limit(n)
+	}
+	return latest
+}
+
+// TopPerTerm returns, for every term in this taxonomy, its first page in
+// weighted order ("weight", the default) or its most recent page ("date").
+// In weighted order, pages with equal weights fall back to the default page
+// sort, i.e. the page weight, then the date, newest first. With "date",
+// pages with equal dates fall back to the weighted order. Terms without
+// pages are left out.
+func (i Taxonomy) TopPerTerm(order ...string) (map[string]page.Page, error) {
+	byDate := false
+	if len(order) > 0 {
+		switch order[0] {
+		case "weight":
+		case "date":
+			byDate = true
+		default:
+			return nil, fmt.Errorf("invalid top per term order %q, must be weight or date", order[0])
+		}
+	}
+
+	top := make(map[string]page.Page, len(i))
+	for k, v := range i {
+		if len(v) == 0 {
+			continue
+		}
+		weighted := make(page.WeightedPages, len(v))
+		copy(weighted, v)
+		weighted.Sort()
+
+		first := weighted[0].Page
+		if byDate {
+			for _, w := range weighted[1:] {
+				if w.Page.Date().After(first.Date()) {
+					first = w.Page
+				}
+			}
+		}
+		top[k] = first
+	}
+	return top, nil
+}
+
+// DateBucket is a group of pages published in the same year or month.
+// See Taxonomy.DateBuckets.
+type DateBucket struct {
+	// The year, 0 for the pages without a date.
+	Year int
+
+	// The month, 0 when grouping by year or for the pages without a date.
+	Month int
+
+	Pages page.Pages
+}
+
+// DateBuckets returns the pages for the given key grouped by "year" or
+// "month", newest first. The pages in each bucket are ordered by date
+// descending. Pages without a date are put in a trailing bucket with a
+// zero Year.
+func (i Taxonomy) DateBuckets(key, granularity string) ([]DateBucket, error) {
+	byMonth := false
+	switch granularity {
+	case "year":
+	case "month":
+		byMonth = true
+	default:
+		return nil, fmt.Errorf("invalid date bucket granularity %q, must be year or month", granularity)
+	}
+
+	var (
+		buckets []DateBucket
+		undated page.Pages
+	)
+
+	for _, p := range i[key].Pages().ByDate().Reverse() {
+		d := p.Date()
+		if d.IsZero() {
+			undated = append(undated, p)
+			continue
+		}
+		year, month := d.Year(), 0
+		if byMonth {
+			month = int(d.Month())
+		}
+		if n := len(buckets); n == 0 || buckets[n-1].Year != year || buckets[n-1].Month != month {
+			buckets = append(buckets, DateBucket{Year: year, Month: month})
+		}
+		buckets[len(buckets)-1].Pages = append(buckets[len(buckets)-1].Pages, p)
+	}
+
+	if undated != nil {
+		buckets = append(buckets, DateBucket{Pages: undated})
+	}
+
+	return buckets, nil
+}
+
+// Permalink returns the permalink of the term page for the given term, empty
+// if the term is unknown or its page isn't rendered. The term can be given
+// as the normalized term key, e.g. "hugo-tips", or as written in the front
+// matter, e.g. "Hugo Tips".
+func (i Taxonomy) Permalink(term string) string {
+	pages, found := i[term]
+	if !found {
+		for key, wp := range i {
+			if strings.EqualFold(key, term) || strings.EqualFold(termOf(wp), term) {
+				pages = wp
+				break
+			}
+		}
+	}
+
+	if len(pages) == 0 {
+		return ""
+	}
+	if owner := pages.Page(); owner != nil {
+		return owner.Permalink()
+	}
+	return ""
+}
+
+// termOf returns the term as written in the front matter for the pages of a
+// taxonomy term, empty if not known.
+func termOf(wp page.WeightedPages) string {
+	if len(wp) == 0 {
+		return ""
+	}
+	if ps, ok := wp.Page().(*pageState); ok {
+		if info := ps.getTaxonomyNodeInfo(); info != nil {
+			return info.term
+		}
+	}
+	return ""
+}
+
+// ExcludeTerm returns the pages assigned to any term in this taxonomy other
+// than key, each listed once, in the default page order.
+func (i Taxonomy) ExcludeTerm(key string) page.Pages {
+	seen := make(map[page.Page]bool)
+	var pages page.Pages
+	for k, v := range i {
+		if k == key {
+			continue
+		}
+		for _, w := range v {
+			if !seen[w.Page] {
+				seen[w.Page] = true
+				pages = append(pages, w.Page)
+			}
+		}
+	}
+	page.SortByDefault(pages)
+	return pages
+}
+
+// Diff returns the pages assigned to keyA that are not assigned to keyB, in
+// keyA's order. Pages are compared by identity. All of keyA's pages are
+// returned if keyB is unknown, none if keyA is unknown.
+func (i Taxonomy) Diff(keyA, keyB string) page.Pages {
+	var pages page.Pages
+	for _, w := range i[keyA] {
+		if !containsPage(i[keyB], w.Page) {
+			pages = append(pages, w.Page)
+		}
+	}
+	return pages
+}
+
+// intersect returns the weighted pages of the first key that are also
+// assigned to all of the other keys.
+func (i Taxonomy) intersect(keys ...string) page.WeightedPages {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var pages page.WeightedPages
+
+	for _, w := range i[keys[0]] {
+		inAll := true
+		for _, key := range keys[1:] {
+			if !containsPage(i[key], w.Page) {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			pages = append(pages, w)
+		}
+	}
+
+	return pages
+}
+
+// Merge returns a new taxonomy with the terms of both i and other. The
+// weighted pages of terms present in both are concatenated and sorted, with
+// each page listed once, also if it's listed more than once in i or other.
+// If a page is assigned to the same term in both, the first entry (and
+// weight) from i wins. Neither i nor other is modified.
+func (i Taxonomy) Merge(other Taxonomy) Taxonomy {
+	merged := make(Taxonomy, len(i)+len(other))
+	for _, t := range []Taxonomy{i, other} {
+		for k, v := range t {
+			pages := merged[k]
+			for _, w := range v {
+				if !containsPage(pages, w.Page) {
+					pages = append(pages, w)
+				}
+			}
+			merged[k] = pages
+		}
+	}
+	for _, pages := range merged {
+		pages.Sort()
+	}
+	return merged
+}
+
 func (i Taxonomy) add(key string, w page.WeightedPage) {
 	i[key] = append(i[key], w)
 }
@@ -83,17 +607,155 @@ func (i Taxonomy) Alphabetical() OrderedTaxonomy {
 	return ia
 }
 
+// TaxonomyLetterGroup is a group of taxonomy terms sharing the first letter.
+// See Taxonomy.ByFirstLetter.
+type TaxonomyLetterGroup struct {
+	// The uppercased first letter, or "#" for terms not starting with a letter.
+	Letter string
+
+	// The terms, sorted by name.
+	Entries OrderedTaxonomy
+}
+
+// ByFirstLetter returns the terms grouped by their first letter, e.g. for an
+// A-Z index. Terms starting with a digit or symbol are grouped under "#",
+// which comes first; the other groups are sorted by letter.
+func (i Taxonomy) ByFirstLetter() []TaxonomyLetterGroup {
+	var groups []TaxonomyLetterGroup
+	index := make(map[string]int)
+
+	for _, ie := range i.Alphabetical() {
+		letter := "#"
+		if r, _ := utf8.DecodeRuneInString(ie.Name); unicode.IsLetter(r) {
+			letter = string(unicode.ToUpper(r))
+		}
+		idx, found := index[letter]
+		if !found {
+			idx = len(groups)
+			index[letter] = idx
+			groups = append(groups, TaxonomyLetterGroup{Letter: letter})
+		}
+		groups[idx].Entries = append(groups[idx].Entries, ie)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Letter == "#" || groups[j].Letter == "#" {
+			return groups[i].Letter == "#" && groups[j].Letter != "#"
+		}
+		return compare.LessStrings(groups[i].Letter, groups[j].Letter)
+	})
+
+	return groups
+}
+
+// TaxonomyNode is a term in a navigation tree of a taxonomy. See Taxonomy.Tree.
+type TaxonomyNode struct {
+	// The last element of the term's path, e.g. "go" for "languages/go".
+	Name string
+
+	// The term key, e.g. "languages/go".
+	Term string
+
+	// The number of pages assigned to the term, 0 for a parent that isn't
+	// a term itself.
+	Count int
+
+	// The permalink of the term page, empty if there is none.
+	Permalink string
+
+	Children []*TaxonomyNode
+}
+
+// Tree returns the terms as a tree for navigation. Terms are nested by their
+// slash separated path, e.g. "languages/go" is a child of "languages", so a
+// taxonomy without such terms gives a single level. The nodes in each level
+// are sorted by name ("alphabetical") or by count ("count"), descending, then
+// by name.
+func (i Taxonomy) Tree(order string) ([]*TaxonomyNode, error) {
+	var less func(n1, n2 *TaxonomyNode) bool
+	switch order {
+	case "alphabetical":
+		less = func(n1, n2 *TaxonomyNode) bool {
+			return compare.LessStrings(n1.Name, n2.Name)
+		}
+	case "count":
+		less = func(n1, n2 *TaxonomyNode) bool {
+			if n1.Count == n2.Count {
+				return compare.LessStrings(n1.Name, n2.Name)
+			}
+			return n1.Count > n2.Count
+		}
+	default:
+		return nil, fmt.Errorf("invalid taxonomy tree order %q, must be alphabetical or count", order)
+	}
+
+	root := &TaxonomyNode{}
+	nodes := map[string]*TaxonomyNode{"": root}
+
+	var getOrCreate func(term string) *TaxonomyNode
+	getOrCreate = func(term string) *TaxonomyNode {
+		if n, found := nodes[term]; found {
+			return n
+		}
+		parent, name := "", term
+		if idx := strings.LastIndex(term, "/"); idx != -1 {
+			parent, name = term[:idx], term[idx+1:]
+		}
+		n := &TaxonomyNode{Name: name, Term: term}
+		nodes[term] = n
+		p := getOrCreate(parent)
+		p.Children = append(p.Children, n)
+		return n
+	}
+
+	for key, wp := range i {
+		term := strings.Trim(key, "/")
+		if term == "" {
+			continue
+		}
+		n := getOrCreate(term)
+		n.Count = len(wp)
+		if owner := wp.Page(); owner != nil {
+			n.Permalink = owner.Permalink()
+		}
+	}
+
+	var sortNodes func(nodes []*TaxonomyNode)
+	sortNodes = func(nodes []*TaxonomyNode) {
+		sort.SliceStable(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+		for _, n := range nodes {
+			sortNodes(n.Children)
+		}
+	}
+	sortNodes(root.Children)
+
+	return root.Children, nil
+}
+
 // ByCount returns an ordered taxonomy sorted by # of pages per key.
 // If taxonomies have the same # of pages, sort them alphabetical
 func (i Taxonomy) ByCount() OrderedTaxonomy {
+	return i.ByCountSorted(true, false)
+}
+
+// ByCountSorted returns an ordered taxonomy sorted by # of pages per key,
+// descending if countDesc is set. Keys with the same # of pages are sorted
+// by name, reverse alphabetical if nameDesc is set.
+func (i Taxonomy) ByCountSorted(countDesc, nameDesc bool) OrderedTaxonomy {
 	count := func(i1, i2 *OrderedTaxonomyEntry) bool {
 		li1 := len(i1.WeightedPages)
 		li2 := len(i2.WeightedPages)
 
 		if li1 == li2 {
+			if nameDesc {
+				return compare.LessStrings(i2.Name, i1.Name)
+			}
 			return compare.LessStrings(i1.Name, i2.Name)
 		}
-		return li1 > li2
+		if countDesc {
+			return li1 > li2
+		}
+		return li1 < li2
 	}
 
 	ia := i.TaxonomyArray()
@@ -125,6 +787,30 @@ func (t OrderedTaxonomy) Reverse() OrderedTaxonomy {
 	return t
 }
 
+// Len returns the number of terms in this ordered taxonomy.
+func (t OrderedTaxonomy) Len() int {
+	return len(t)
+}
+
+// Slice returns a copy of the terms from index start up to, but not
+// including, end, e.g. to build a pager over the terms. The indices are
+// clamped to the valid range; the result is empty if start is not before end.
+func (t OrderedTaxonomy) Slice(start, end int) OrderedTaxonomy {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(t) {
+		end = len(t)
+	}
+	if start >= end {
+		return OrderedTaxonomy{}
+	}
+
+	s := make(OrderedTaxonomy, end-start)
+	copy(s, t[start:end])
+	return s
+}
+
 // A type to implement the sort interface for TaxonomyEntries.
 type orderedTaxonomySorter struct {
 	taxonomy OrderedTaxonomy
@@ -157,6 +843,16 @@ func (s *orderedTaxonomySorter) Less(i, j int) bool {
 	return s.by(&s.taxonomy[i], &s.taxonomy[j])
 }
 
+// containsPage reports whether p is in wp, compared by identity.
+func containsPage(wp page.WeightedPages, p page.Page) bool {
+	for _, w := range wp {
+		if w.Page == p {
+			return true
+		}
+	}
+	return false
+}
+
 // taxonomyNodeInfo stores additional metadata about a taxonomy.
 type taxonomyNodeInfo struct {
 	plural string
@@ -170,26 +866,51 @@ type taxonomyNodeInfo struct {
 	termKey string
 
 	// The original, unedited term name. Useful for titles etc.
+	// Pages may spell a term differently, e.g. "Hugo" and "hugo", so this
+	// is the spelling of the first page in the default sort order.
 	term string
 
+	// The page term was taken from.
+	termPage page.Page
+
 	dates resource.Dates
 
 	parent *taxonomyNodeInfo
 
+	// Whether this node represents a configured intersection of terms.
+	intersection bool
+
 	// Either of Kind taxonomyTerm (parent) or taxonomy
 	owner *page.PageWrapper
 }
 
+// setTermFrom sets the term to the spelling used in p if p sorts before
+// the page it was taken from, so the term is the same on every build.
+func (t *taxonomyNodeInfo) setTermFrom(term string, p page.Page) {
+	if t.termPage == nil || page.DefaultPageSort(p, t.termPage) {
+		t.term = term
+		t.termPage = p
+	}
+}
+
 func (t *taxonomyNodeInfo) UpdateFromPage(p page.Page) {
 
 	// Select the latest dates
 	t.dates.UpdateDateAndLastmodIfAfter(p)
 }
 
+// TransferValues sets p as the owner of this node. The date and lastmod
+// not set for p, e.g. a term page without front matter dates, are set to
+// the newest of the member pages. They are transferred one by one, as a
+// lastmod from the file's modification time alone would otherwise leave
+// the date zero.
 func (t *taxonomyNodeInfo) TransferValues(p *pageState) {
 	t.owner.Page = p
-	if p.Lastmod().IsZero() && p.Date().IsZero() {
-		p.m.Dates.UpdateDateAndLastmodIfAfter(t.dates)
+	if p.Date().IsZero() {
+		p.m.Dates.FDate = t.dates.Date()
+	}
+	if p.Lastmod().IsZero() {
+		p.m.Dates.FLastmod = t.dates.Lastmod()
 	}
 }
 
@@ -238,6 +959,25 @@ func (t taxonomyNodeInfos) GetOrCreate(plural, term string) *taxonomyNodeInfo {
 	return n
 }
 
+// sortedNodes returns all the taxonomy nodes ordered by plural, then by term
+// key, with the taxonomy node itself first, so the pages built from them are
+// created in the same order on every build.
+func (t taxonomyNodeInfos) sortedNodes() []*taxonomyNodeInfo {
+	nodes := make([]*taxonomyNodeInfo, 0, len(t.m))
+	for _, n := range t.m {
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].plural != nodes[j].plural {
+			return nodes[i].plural < nodes[j].plural
+		}
+		return nodes[i].termKey < nodes[j].termKey
+	})
+
+	return nodes
+}
+
 func (t taxonomyNodeInfos) Get(sections ...string) *taxonomyNodeInfo {
 	key := t.key(sections...)
 
@@ -15,6 +15,7 @@ package hugolib
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 
 	"github.com/gohugoio/hugo/resources/page"
@@ -322,3 +323,842 @@ Content.
 	b.AssertFileContent("public/tags/index.html", `<li><a href="http://example.com/tags/rocks-i-say/">Rocks I say!</a> 10</li>`)
 
 }
+
+func TestTaxonomiesTermPageDates(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[frontmatter]
+lastmod = [":default", ":fileModTime"]
+[sitemap]
+lastmodSource = "date"
+`)
+	b.WithTemplates(
+		"_default/list.html", `{{ .Kind }}|{{ .Date.Format "2006-01-02" }}|{{ .Lastmod.Format "2006-01-02" }}`,
+		"_default/single.html", `{{ .Title }}`,
+	)
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ndate: 2019-01-01\nlastmod: 2019-04-01\ntags: [a, b]\n---\n",
+		"p2.md", "---\ntitle: p2\ndate: 2019-03-01\ntags: [a]\n---\n",
+		"p3.md", "---\ntitle: p3\ndate: 2019-02-01\ntags: [b]\n---\n",
+		// No front matter dates, so the lastmod is the file's modification time.
+		"tags/b/_index.md", "---\ntitle: B\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/tags/a/index.html", "taxonomy|2019-03-01|2019-04-01")
+	b.AssertFileContent("public/tags/index.html", "taxonomyTerm|2019-03-01|2019-04-01")
+	b.AssertFileContent("public/tags/b/index.html", "taxonomy|2019-02-01|")
+	b.AssertFileContent("public/sitemap.xml", `<loc>http://example.com/tags/b/</loc>
+    <lastmod>2019-02-01T00:00:00+00:00</lastmod>`)
+}
+
+func TestTaxonomyLatestPerTerm(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+
+	for i := 1; i <= 4; i++ {
+		tags := `["a", "b"]`
+		if i > 2 {
+			tags = `["a"]`
+		}
+		b.WithContent(fmt.Sprintf("p%d.md", i), fmt.Sprintf(`---
+title: "p%d"
+date: "2019-01-0%d"
+tags: %s
+---
+`, i, i, tags))
+	}
+
+	b.CreateSites().Build(BuildCfg{})
+
+	latest := b.H.Sites[0].Taxonomies["tags"].LatestPerTerm(3)
+
+	titles := func(pages page.Pages) string {
+		var s []string
+		for _, p := range pages {
+			s = append(s, p.Title())
+		}
+		return strings.Join(s, ",")
+	}
+
+	assert.Len(latest, 2)
+	assert.Equal("p4,p3,p2", titles(latest["a"]))
+	assert.Equal("p2,p1", titles(latest["b"]))
+	assert.Len(b.H.Sites[0].Taxonomies["tags"].LatestPerTerm(0)["a"], 0)
+}
+
+func TestTaxonomyTopPerTerm(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ndate: 2019-01-01\ntags: [a, b]\ntags_weight: 10\n---\n",
+		"p2.md", "---\ntitle: p2\ndate: 2019-03-01\ntags: [a]\ntags_weight: 10\n---\n",
+		"p3.md", "---\ntitle: p3\ndate: 2019-05-01\ntags: [a]\ntags_weight: 20\n---\n",
+		"p4.md", "---\ntitle: p4\ndate: 2018-01-01\ntags: [b]\ntags_weight: 5\n---\n",
+	)
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := Taxonomy{"empty": nil}
+	for k, v := range b.H.Sites[0].Taxonomies["tags"] {
+		tags[k] = v
+	}
+
+	top, err := tags.TopPerTerm()
+	assert.NoError(err)
+	assert.Len(top, 2)
+	// p1 and p2 have equal weights, so the newest comes first.
+	assert.Equal("p2", top["a"].Title())
+	assert.Equal("p4", top["b"].Title())
+
+	top, err = tags.TopPerTerm("date")
+	assert.NoError(err)
+	assert.Len(top, 2)
+	assert.Equal("p3", top["a"].Title())
+	assert.Equal("p1", top["b"].Title())
+
+	_, err = tags.TopPerTerm("title")
+	assert.Error(err)
+}
+
+func TestTaxonomyByCountSorted(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	taxonomy := Taxonomy{
+		"a": make(page.WeightedPages, 1),
+		"b": make(page.WeightedPages, 2),
+		"c": make(page.WeightedPages, 2),
+		"d": make(page.WeightedPages, 3),
+	}
+
+	names := func(ot OrderedTaxonomy) string {
+		var s []string
+		for _, e := range ot {
+			s = append(s, e.Name)
+		}
+		return strings.Join(s, ",")
+	}
+
+	assert.Equal("d,b,c,a", names(taxonomy.ByCountSorted(true, false)))
+	assert.Equal("d,c,b,a", names(taxonomy.ByCountSorted(true, true)))
+	assert.Equal("a,b,c,d", names(taxonomy.ByCountSorted(false, false)))
+	assert.Equal("a,c,b,d", names(taxonomy.ByCountSorted(false, true)))
+	assert.Equal(names(taxonomy.ByCountSorted(true, false)), names(taxonomy.ByCount()))
+}
+
+func TestOrderedTaxonomySlice(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	taxonomy := Taxonomy{
+		"a": make(page.WeightedPages, 1),
+		"b": make(page.WeightedPages, 2),
+		"c": make(page.WeightedPages, 3),
+		"d": make(page.WeightedPages, 4),
+	}
+
+	names := func(ot OrderedTaxonomy) string {
+		var s []string
+		for _, e := range ot {
+			s = append(s, e.Name)
+		}
+		return strings.Join(s, ",")
+	}
+
+	ordered := taxonomy.Alphabetical()
+	assert.Equal(4, ordered.Len())
+
+	for _, test := range []struct {
+		start, end int
+		expected   string
+	}{
+		{0, 2, "a,b"},
+		{1, 3, "b,c"},
+		{2, 10, "c,d"},
+		{-5, 1, "a"},
+		{-5, 10, "a,b,c,d"},
+		{3, 1, ""},
+		{2, 2, ""},
+		{4, 6, ""},
+		{-3, -1, ""},
+	} {
+		sliced := ordered.Slice(test.start, test.end)
+		assert.NotNil(sliced)
+		assert.Equal(test.expected, names(sliced), fmt.Sprintf("%d:%d", test.start, test.end))
+	}
+
+	// The slice is a copy.
+	ordered.Slice(0, 3).Reverse()
+	assert.Equal("a,b,c,d", names(ordered))
+}
+
+func TestTaxonomyMerge(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [a]\ntags_weight: 10\n---",
+		"p2.md", "---\ntitle: p2\ntags: [a, b]\ntags_weight: 20\ncategories: [a]\ncategories_weight: 5\n---",
+		"p3.md", "---\ntitle: p3\ncategories: [a, c]\ncategories_weight: 15\n---",
+	)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+	categories := b.H.Sites[0].Taxonomies["categories"]
+
+	titles := func(wp page.WeightedPages) string {
+		var s []string
+		for _, w := range wp {
+			s = append(s, fmt.Sprintf("%s:%d", w.Page.Title(), w.Weight))
+		}
+		return strings.Join(s, ",")
+	}
+
+	merged := tags.Merge(categories)
+
+	assert.Len(merged, 3)
+	assert.Equal("p1:10,p3:15,p2:20", titles(merged["a"]))
+	assert.Equal("p2:20", titles(merged["b"]))
+	assert.Equal("p3:15", titles(merged["c"]))
+	assert.Equal("p2:5,p1:10,p3:15", titles(categories.Merge(tags)["a"]))
+
+	// The inputs are left untouched.
+	assert.Equal("p1:10,p2:20", titles(tags["a"]))
+	assert.Equal("p2:5,p3:15", titles(categories["a"]))
+	assert.Len(tags, 2)
+
+	// Pages listed more than once in either taxonomy are listed once,
+	// with the first entry of the receiver.
+	p1, p2 := tags["a"][0], tags["a"][1]
+	p2Light := page.NewWeightedPage(1, p2.Page, nil)
+	dup := Taxonomy{"a": page.WeightedPages{p2, p1, p2Light, p1}}
+	assert.Equal("p1:10,p2:20", titles(dup.Merge(Taxonomy{})["a"]))
+	assert.Equal("p1:10,p3:15,p2:20", titles(dup.Merge(categories)["a"]))
+	assert.Equal("p1:10,p3:15,p2:20", titles(Taxonomy{}.Merge(dup).Merge(categories)["a"]))
+}
+
+func TestTaxonomyExcludeTerm(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\nweight: 1\ntags: [a]\n---",
+		"p2.md", "---\ntitle: p2\nweight: 2\ntags: [a, b]\n---",
+		"p3.md", "---\ntitle: p3\nweight: 3\ntags: [b, c]\n---",
+		"p4.md", "---\ntitle: p4\nweight: 4\ntags: [c]\n---",
+	)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	titles := func(pages page.Pages) string {
+		var s []string
+		for _, p := range pages {
+			s = append(s, p.Title())
+		}
+		return strings.Join(s, ",")
+	}
+
+	assert.Equal("p2,p3,p4", titles(tags.ExcludeTerm("a")))
+	assert.Equal("p1,p2,p3", titles(tags.ExcludeTerm("c")))
+	assert.Equal("p1,p2,p3,p4", titles(tags.ExcludeTerm("unknown")))
+}
+
+func TestTaxonomyDiff(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\nweight: 1\ntags: [a]\n---",
+		"p2.md", "---\ntitle: p2\nweight: 2\ntags: [a, b]\n---",
+		"p3.md", "---\ntitle: p3\nweight: 3\ntags: [a, b, c]\n---",
+		"p4.md", "---\ntitle: p4\nweight: 4\ntags: [c]\ntags_weight: 1\n---",
+		"p5.md", "---\ntitle: p5\nweight: 5\ntags: [a, d]\ntags_weight: -1\n---",
+	)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	titles := func(pages page.Pages) string {
+		var s []string
+		for _, p := range pages {
+			s = append(s, p.Title())
+		}
+		return strings.Join(s, ",")
+	}
+
+	// Overlapping terms, in the order of the first.
+	assert.Equal("p5,p1", titles(tags.Diff("a", "b")))
+	assert.Equal("p5,p1,p2", titles(tags.Diff("a", "c")))
+	assert.Equal("", titles(tags.Diff("b", "a")))
+	assert.Equal("p4", titles(tags.Diff("c", "b")))
+	// Disjoint terms.
+	assert.Equal("p2,p3", titles(tags.Diff("b", "d")))
+	assert.Equal("p5", titles(tags.Diff("d", "c")))
+	// Unknown terms.
+	assert.Equal("p5,p1,p2,p3", titles(tags.Diff("a", "unknown")))
+	assert.Len(tags.Diff("unknown", "a"), 0)
+}
+
+func TestTaxonomyCountByKind(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: P1\ntags: [go]\n---",
+		"p2.md", "---\ntitle: P2\ntags: [go]\n---",
+		"blog/_index.md", "---\ntitle: Blog\ntags: [go]\n---",
+		"blog/p3.md", "---\ntitle: P3\ntags: [go, rust]\n---",
+		"_index.md", "---\ntitle: Home\ntags: [rust]\n---",
+	)
+	b.WithTemplatesAdded("_default/taxonomy.html", `{{ $tags := .Site.Taxonomies.tags }}{{ $tags.CountByKind .Data.Term "page" }} pages, {{ $tags.CountByKind .Data.Term "section" }} sections`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	assert.Equal(3, tags.CountByKind("go", page.KindPage))
+	assert.Equal(1, tags.CountByKind("go", page.KindSection))
+	assert.Equal(0, tags.CountByKind("go", page.KindHome))
+	assert.Equal(1, tags.CountByKind("rust", page.KindHome))
+	assert.Equal(0, tags.CountByKind("go", "unknown"))
+	assert.Equal(0, tags.CountByKind("unknown", page.KindPage))
+
+	b.AssertFileContent("public/tags/go/index.html", "3 pages, 1 sections")
+}
+
+func TestTaxonomyDateBuckets(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: P1\ndate: 2018-03-01\ntags: [go]\n---",
+		"p2.md", "---\ntitle: P2\ndate: 2019-01-10\ntags: [go]\n---",
+		"p3.md", "---\ntitle: P3\ndate: 2019-01-20\ntags: [go]\n---",
+		"p4.md", "---\ntitle: P4\ndate: 2019-05-01\ntags: [go]\n---",
+		"p5.md", "---\ntitle: P5\ntags: [go]\n---",
+	)
+	b.WithTemplatesAdded("_default/taxonomy.html", `{{ range .Site.Taxonomies.tags.DateBuckets .Data.Term "month" }}{{ .Year }}-{{ .Month }}:{{ range .Pages }}{{ .Title }}|{{ end }}
+{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	bucketsToString := func(buckets []DateBucket) string {
+		var s string
+		for _, bucket := range buckets {
+			s += fmt.Sprintf("%d-%d:", bucket.Year, bucket.Month)
+			for _, p := range bucket.Pages {
+				s += p.Title() + "|"
+			}
+			s += " "
+		}
+		return s
+	}
+
+	byYear, err := tags.DateBuckets("go", "year")
+	assert.NoError(err)
+	assert.Equal("2019-0:P4|P3|P2| 2018-0:P1| 0-0:P5| ", bucketsToString(byYear))
+
+	byMonth, err := tags.DateBuckets("go", "month")
+	assert.NoError(err)
+	assert.Equal("2019-5:P4| 2019-1:P3|P2| 2018-3:P1| 0-0:P5| ", bucketsToString(byMonth))
+
+	none, err := tags.DateBuckets("unknown", "year")
+	assert.NoError(err)
+	assert.Len(none, 0)
+
+	_, err = tags.DateBuckets("go", "week")
+	assert.Error(err)
+
+	b.AssertFileContent("public/tags/go/index.html", "2019-5:P4|", "2019-1:P3|P2|", "2018-3:P1|", "0-0:P5|")
+}
+
+func TestTaxonomyContains(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: Same\ntags: [go]\n---",
+		"p2.md", "---\ntitle: Same\ntags: [rust]\n---",
+		"p3.md", "---\ntitle: Other\ntags: [go, rust]\n---",
+	)
+	b.WithTemplatesAdded("_default/single.html", `{{ if .Site.Taxonomies.tags.Contains "go" . }}Go: yes{{ else }}Go: no{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+	p1 := b.H.Sites[0].getPage(page.KindPage, "p1.md")
+	p2 := b.H.Sites[0].getPage(page.KindPage, "p2.md")
+	assert.Equal(p1.Title(), p2.Title())
+
+	assert.True(tags.Contains("go", p1))
+	assert.False(tags.Contains("go", p2))
+	assert.True(tags.Contains("rust", p2))
+	assert.False(tags.Contains("unknown", p1))
+
+	b.AssertFileContent("public/p1/index.html", "Go: yes")
+	b.AssertFileContent("public/p2/index.html", "Go: no")
+	b.AssertFileContent("public/p3/index.html", "Go: yes")
+}
+
+func TestTaxonomyWeightOf(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: P1\ntags: [go]\ntags_weight: 5\n---",
+		"p2.md", "---\ntitle: P2\ntags: [go, rust]\n---",
+		"p3.md", "---\ntitle: P3\ntags: [rust]\ntags_weight: 7\n---",
+	)
+	b.WithTemplatesAdded("_default/single.html", `Weight: {{ .Site.Taxonomies.tags.WeightOf "go" . }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+	p1 := b.H.Sites[0].getPage(page.KindPage, "p1.md")
+	p2 := b.H.Sites[0].getPage(page.KindPage, "p2.md")
+	p3 := b.H.Sites[0].getPage(page.KindPage, "p3.md")
+
+	assert.Equal(5, tags.WeightOf("go", p1))
+	assert.Equal(0, tags.WeightOf("go", p2))
+	assert.Equal(0, tags.WeightOf("go", p3))
+	assert.Equal(7, tags.WeightOf("rust", p3))
+	assert.Equal(0, tags.WeightOf("unknown", p1))
+
+	b.AssertFileContent("public/p1/index.html", "Weight: 5")
+	b.AssertFileContent("public/p3/index.html", "Weight: 0")
+}
+
+func TestSiteOrphanTerms(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: P1\ntags: [used]\n---",
+		"tags/used/_index.md", "---\ntitle: Used\n---",
+		"tags/zombie/_index.md", "---\ntitle: Zombie\n---",
+		"tags/abandoned/_index.md", "---\ntitle: Abandoned\n---",
+		"categories/empty/_index.md", "---\ntitle: Empty\n---",
+	)
+	b.WithTemplatesAdded("index.html", `{{ range .Site.OrphanTerms }}{{ .Plural }}/{{ .Term }}|{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	assert.Equal([]OrphanTerm{
+		{Plural: "categories", Term: "empty"},
+		{Plural: "tags", Term: "abandoned"},
+		{Plural: "tags", Term: "zombie"},
+	}, b.H.Sites[0].Info.OrphanTerms())
+
+	b.AssertFileContent("public/index.html", "categories/empty|tags/abandoned|tags/zombie|")
+}
+
+func TestTaxonomyByFirstLetter(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: P1\ntags: [banana, Apple, 42, éclair]\n---",
+		"p2.md", "---\ntitle: P2\ntags: [avocado, _hidden, Øl, berry]\n---",
+	)
+	b.WithTemplatesAdded("index.html", `{{ range .Site.Taxonomies.tags.ByFirstLetter }}{{ .Letter }}:{{ range .Entries }} {{ .Name }}{{ end }}|{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	groups := b.H.Sites[0].Taxonomies["tags"].ByFirstLetter()
+
+	var letters []string
+	for _, g := range groups {
+		letters = append(letters, g.Letter)
+	}
+	assert.Equal([]string{"#", "A", "B", "É", "Ø"}, letters)
+	assert.Equal([]string{"42", "_hidden"}, termNames(groups[0].Entries))
+	assert.Equal([]string{"apple", "avocado"}, termNames(groups[1].Entries))
+
+	b.AssertFileContent("public/index.html", "#: 42 _hidden|A: apple avocado|B: banana berry|É: éclair|Ø: øl|")
+}
+
+func termNames(ot OrderedTaxonomy) []string {
+	var names []string
+	for _, ie := range ot {
+		names = append(names, ie.Name)
+	}
+	return names
+}
+
+func TestTaxonomyIntersections(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+paginate = 1
+[taxonomies]
+tag = "tags"
+[taxonomyIntersections]
+tags = ["Go+Web", "go+hugo", "go+missing"]
+`)
+
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [\"Go\", \"Web\"]\ndate: 2019-01-01\n---\n",
+		"p2.md", "---\ntitle: p2\ntags: [\"Go\", \"Web\", \"Hugo\"]\ndate: 2019-01-02\n---\n",
+		"p3.md", "---\ntitle: p3\ntags: [\"Go\"]\n---\n",
+	)
+
+	b.WithTemplatesAdded("_default/taxonomy.html", `Taxonomy: {{ .Title }}|{{ range .Data.Pages }}{{ .Title }}|{{ end }}{{ .Paginator.TotalPages }}`)
+	b.WithTemplatesAdded("_default/terms.html", `Terms: {{ range .Pages }}{{ .Title }}|{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	s := b.H.Sites[0]
+
+	assert.Len(s.Taxonomies["tags"], 3)
+
+	b.AssertFileContent("public/tags/go+web/index.html", "Taxonomy: Go &#43; Web|p2|p1|2")
+	b.AssertFileContent("public/tags/go+web/page/2/index.html", "Taxonomy: Go &#43; Web")
+	b.AssertFileContent("public/tags/go+hugo/index.html", "Taxonomy: go &#43; hugo|p2|1")
+	b.AssertFileContent("public/tags/go+web/index.xml", `<atom:link href="http://example.com/tags/go+web/index.xml" rel="self"`)
+	b.AssertFileContent("public/tags/index.html", "Terms: Go|Hugo|Web|")
+	assert.False(b.CheckExists("public/tags/go+missing/index.html"))
+}
+
+func TestTaxonomyTermsJSON(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+[outputs]
+taxonomyTerm = ["HTML", "JSON"]
+`)
+	b.WithTemplates("_default/single.html", "{{ .Title }}", "_default/list.html", "{{ .Title }}")
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [\"Hugo\", \"<script>\"]\n---\n",
+		"p2.md", "---\ntitle: p2\ntags: [\"Hugo\", \"Go\"]\ncategories: [\"Web\"]\n---\n",
+	)
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/tags/index.json", `{"taxonomy":"tags","terms":[`+
+		`{"count":1,"name":"go","permalink":"http://example.com/tags/go/","title":"Go"},`+
+		`{"count":2,"name":"hugo","permalink":"http://example.com/tags/hugo/","title":"Hugo"},`+
+		`{"count":1,"name":"script","permalink":"http://example.com/tags/script/","title":"\u003cscript\u003e"}]}`)
+	b.AssertFileContent("public/categories/index.json", `{"taxonomy":"categories","terms":[{"count":1,"name":"web"`)
+}
+
+func TestTaxonomyListNewestTerms(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ndate: 2019-01-01\ntags: [a]\ncategories: [x]\n---",
+		"p2.md", "---\ntitle: p2\ndate: 2019-03-01\ntags: [a, b]\n---",
+		"p3.md", "---\ntitle: p3\ndate: 2019-02-01\ncategories: [z]\n---",
+	)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	terms := b.H.Sites[0].Taxonomies.NewestTerms(0)
+
+	var got []string
+	for _, term := range terms {
+		got = append(got, fmt.Sprintf("%s/%s:%d:%s", term.Plural, term.Term, term.Count, term.Date.Format("2006-01-02")))
+	}
+
+	assert.Equal([]string{"tags/a:2:2019-03-01", "tags/b:1:2019-03-01", "categories/z:1:2019-02-01", "categories/x:1:2019-01-01"}, got)
+	assert.Equal("http://example.com/tags/a/", terms[0].Permalink)
+
+	assert.Len(b.H.Sites[0].Taxonomies.NewestTerms(2), 2)
+}
+
+func TestTaxonomyListRelatedByTerms(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [a, b]\ncategories: [x]\n---",
+		"p2.md", "---\ntitle: p2\ndate: 2019-01-01\ntags: [a]\n---",
+		"p3.md", "---\ntitle: p3\ntags: [a, b]\ncategories: [x]\n---",
+		"p4.md", "---\ntitle: p4\ndate: 2019-02-01\ncategories: [x]\n---",
+		"p5.md", "---\ntitle: p5\ntags: [c]\n---",
+	)
+	b.WithTemplatesAdded("_default/single.html", `{{ range .Site.Taxonomies.RelatedByTerms . 2 1 }}{{ .Title }}|{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	s := b.H.Sites[0]
+	p1 := s.getPage(page.KindPage, "p1.md")
+	assert.NotNil(p1)
+
+	titles := func(pages page.Pages) []string {
+		var s []string
+		for _, p := range pages {
+			s = append(s, p.Title())
+		}
+		return s
+	}
+
+	assert.Equal([]string{"p3", "p4", "p2"}, titles(s.Taxonomies.RelatedByTerms(p1, 0, 1)))
+	assert.Equal([]string{"p3"}, titles(s.Taxonomies.RelatedByTerms(p1, 0, 2)))
+	assert.Equal([]string{"p3", "p4"}, titles(s.Taxonomies.RelatedByTerms(p1, 2, 0)))
+	assert.Len(s.Taxonomies.RelatedByTerms(s.getPage(page.KindPage, "p5.md"), 0, 1), 0)
+
+	b.AssertFileContent("public/p1/index.html", "p3|p4|")
+}
+
+func TestTaxonomyPermalink(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [Hugo Tips, go]\ncategories: [News]\n---",
+	)
+	b.WithTemplatesAdded("_default/single.html", `{{ range $term, $pages := .Site.Taxonomies.tags }}{{ $term }}:{{ $.Site.Taxonomies.tags.Permalink $term }}|{{ end }}{{ .Site.Taxonomies.Permalink "categories" "News" }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	taxonomies := b.H.Sites[0].Taxonomies
+
+	assert.Equal("http://example.com/tags/hugo-tips/", taxonomies["tags"].Permalink("hugo-tips"))
+	assert.Equal("http://example.com/tags/hugo-tips/", taxonomies["tags"].Permalink("Hugo Tips"))
+	assert.Equal("http://example.com/tags/go/", taxonomies["tags"].Permalink("GO"))
+	assert.Equal("http://example.com/categories/news/", taxonomies.Permalink("categories", "news"))
+	assert.Equal("", taxonomies["tags"].Permalink("unknown"))
+	assert.Equal("", taxonomies.Permalink("unknown", "news"))
+
+	b.AssertFileContent("public/p1/index.html", "go:http://example.com/tags/go/|hugo-tips:http://example.com/tags/hugo-tips/|http://example.com/categories/news/")
+}
+
+func TestTaxonomyCounts(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [Hugo Tips, go]\n---",
+		"p2.md", "---\ntitle: p2\ntags: [Hugo Tips]\n---",
+	)
+	b.WithTemplatesAdded("_default/single.html", `{{ .Site.Taxonomies.tags.Counts | jsonify }}|{{ .Site.Taxonomies.tags.CountsByName | jsonify }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	assert.Equal(map[string]int{"hugo-tips": 2, "go": 1}, tags.Counts())
+	assert.Equal(map[string]int{"Hugo Tips": 2, "go": 1}, tags.CountsByName())
+	assert.Empty(Taxonomy{}.Counts())
+
+	b.AssertFileContent("public/p1/index.html", `{"go":1,"hugo-tips":2}|{"Hugo Tips":2,"go":1}`)
+}
+
+func TestTaxonomyCountsWithin(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [go, web]\ncategories: [tutorial]\n---",
+		"p2.md", "---\ntitle: p2\ntags: [go]\ncategories: [tutorial]\n---",
+		"p3.md", "---\ntitle: p3\ntags: [web, css]\n---",
+		"p4.md", "---\ntitle: p4\ntags: [go]\n---",
+	)
+	b.WithTemplatesAdded("index.html", `Home:{{ $tutorials := .Site.Taxonomies.categories.Get "tutorial" }}{{ .Site.Taxonomies.tags.CountsWithin $tutorials.Pages | jsonify }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	s := b.H.Sites[0]
+	tags := s.Taxonomies["tags"]
+
+	assert.Equal(map[string]int{"go": 2, "web": 1, "css": 0}, tags.CountsWithin(s.Taxonomies["categories"].Get("tutorial").Pages()))
+	assert.Equal(tags.Counts(), tags.CountsWithin(s.RegularPages()))
+	assert.Equal(map[string]int{"go": 0, "web": 0, "css": 0}, tags.CountsWithin(nil))
+	assert.Empty(Taxonomy{}.CountsWithin(s.RegularPages()))
+
+	b.AssertFileContent("public/index.html", `Home:{"css":0,"go":2,"web":1}`)
+}
+
+func BenchmarkTaxonomyCountsWithin(b *testing.B) {
+	var content []string
+	for i := 0; i < 500; i++ {
+		content = append(content, fmt.Sprintf("p%d.md", i), fmt.Sprintf("---\ntitle: p%d\ntags: [t%d, t%d, t%d]\n---", i, i%10, i%30, i%50))
+	}
+
+	sb := newTestSitesBuilder(b).WithSimpleConfigFile()
+	sb.WithContent(content...)
+	sb.CreateSites().Build(BuildCfg{SkipRender: true})
+
+	s := sb.H.Sites[0]
+	tags := s.Taxonomies["tags"]
+	base := s.RegularPages()[:250]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tags.CountsWithin(base)
+	}
+}
+
+func TestTaxonomyListTermsOf(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	content := []string{
+		"p1.md", "---\ntitle: p1\ndate: 2019-03-01\ntags: [Hugo Tips, go, Go]\ncategories: Tutorial\n---",
+		"p2.md", "---\ntitle: p2\ndate: 2019-02-01\ntags: [hugo tips]\n---",
+		"p3.md", "---\ntitle: p3\n---",
+		"p4.md", "---\ntitle: p4\ndate: 2019-01-01\ntags: [HUGO TIPS]\n---",
+	}
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `baseURL = "http://example.com/"`)
+	b.WithContent(content...)
+	b.WithTemplatesAdded("_default/single.html", `{{ range .Site.Taxonomies.TermsOf . }}{{ .Plural }}:{{ range .Terms }} <a href="{{ .Permalink }}">{{ .Name }}</a>{{ end }}|{{ end }}`)
+	b.CreateSites().Build(BuildCfg{})
+
+	s := b.H.Sites[0]
+
+	assert.Equal([]PageTaxonomy{
+		{Plural: "categories", Terms: []PageTerm{{Name: "Tutorial", Key: "tutorial", Permalink: "http://example.com/categories/tutorial/", HasPage: true}}},
+		{Plural: "tags", Terms: []PageTerm{
+			{Name: "Hugo Tips", Key: "hugo-tips", Permalink: "http://example.com/tags/hugo-tips/", HasPage: true},
+			{Name: "go", Key: "go", Permalink: "http://example.com/tags/go/", HasPage: true},
+		}},
+	}, s.Taxonomies.TermsOf(s.getPage(page.KindPage, "p1.md")))
+	assert.Empty(s.Taxonomies.TermsOf(s.getPage(page.KindPage, "p3.md")))
+
+	// The pages spell the term differently, so the name is taken from the
+	// first page in the default sort order, the newest.
+	b.AssertFileContent("public/p2/index.html", `tags: <a href="http://example.com/tags/hugo-tips/">Hugo Tips</a>|`)
+	b.AssertFileContent("public/p4/index.html", `tags: <a href="http://example.com/tags/hugo-tips/">Hugo Tips</a>|`)
+
+	// Terms without a term page have no permalink and can be skipped.
+	b = newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "http://example.com/"
+disableKinds = ["taxonomy"]
+`)
+	b.WithContent(content...)
+	b.WithTemplatesAdded("_default/single.html", `{{ range .Site.Taxonomies.TermsOf . }}{{ .Plural }}:{{ range where .Terms "HasPage" true }} <a href="{{ .Permalink }}">{{ .Name }}</a>{{ end }}|{{ end }}`)
+	b.CreateSites().Build(BuildCfg{})
+
+	s = b.H.Sites[0]
+
+	assert.Equal([]PageTerm{{Name: "Hugo Tips", Key: "hugo-tips"}}, s.Taxonomies.TermsOf(s.getPage(page.KindPage, "p2.md"))[0].Terms)
+	b.AssertFileContent("public/p2/index.html", `tags:|`)
+}
+
+func TestTaxonomyNodeInfosSortedNodes(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [b, a, c]\ncategories: [z, x]\n---",
+		"p2.md", "---\ntitle: p2\ntags: [d]\n---",
+	)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	nodes := b.H.Sites[0].taxonomyNodes
+
+	var keys []string
+	for _, n := range nodes.sortedNodes() {
+		keys = append(keys, path.Join(n.plural, n.termKey))
+	}
+
+	assert.Equal([]string{"categories", "categories/x", "categories/z", "tags", "tags/a", "tags/b", "tags/c", "tags/d"}, keys)
+
+	for i := 0; i < 10; i++ {
+		var again []string
+		for _, n := range nodes.sortedNodes() {
+			again = append(again, path.Join(n.plural, n.termKey))
+		}
+		assert.Equal(keys, again)
+	}
+}
+
+func TestTaxonomyTree(t *testing.T) {
+	t.Parallel()
+
+	assert := require.New(t)
+
+	b := newTestSitesBuilder(t).WithSimpleConfigFile()
+	b.WithContent(
+		"p1.md", "---\ntitle: p1\ntags: [languages/go, hugo]\n---",
+		"p2.md", "---\ntitle: p2\ntags: [languages/go, languages/rust, zen]\n---",
+		"p3.md", "---\ntitle: p3\ntags: [zen]\n---",
+	)
+	b.WithTemplatesAdded("index.html", `{{ with .Site.Taxonomies.Tree "tags" "count" }}{{ range . }}{{ .Name }}:{{ .Count }}[{{ range .Children }}{{ .Name }}:{{ .Count }}:{{ .Permalink }}|{{ end }}]{{ end }}{{ end }}`)
+
+	b.CreateSites().Build(BuildCfg{})
+
+	summary := func(nodes []*TaxonomyNode) string {
+		var parts []string
+		for _, n := range nodes {
+			parts = append(parts, fmt.Sprintf("%s:%d", n.Term, n.Count))
+		}
+		return strings.Join(parts, "|")
+	}
+
+	tags := b.H.Sites[0].Taxonomies["tags"]
+
+	tree, err := tags.Tree("alphabetical")
+	assert.NoError(err)
+	assert.Equal("hugo:1|languages:0|zen:2", summary(tree))
+	assert.Equal("languages/go:2|languages/rust:1", summary(tree[1].Children))
+	assert.Equal("go", tree[1].Children[0].Name)
+	assert.Equal("", tree[1].Permalink)
+	assert.Equal("http://example.com/tags/hugo/", tree[0].Permalink)
+
+	tree, err = tags.Tree("count")
+	assert.NoError(err)
+	assert.Equal("zen:2|hugo:1|languages:0", summary(tree))
+
+	_, err = tags.Tree("weight")
+	assert.Error(err)
+
+	tree, err = b.H.Sites[0].Taxonomies.Tree("unknown", "count")
+	assert.NoError(err)
+	assert.Len(tree, 0)
+
+	b.AssertFileContent("public/index.html", "zen:2[]hugo:1[]languages:0[go:2:http://example.com/tags/languages/go/|rust:1:http://example.com/tags/languages/rust/|]")
+}
@@ -191,6 +191,8 @@ func toSortedLanguages(cfg config.Provider, l map[string]interface{}) (Languages
 				language.ContentDir = filepath.Clean(cast.ToString(v))
 			case "disabled":
 				language.Disabled = cast.ToBool(v)
+			case "hreflangaliases":
+				language.HreflangAliases = cast.ToStringSlice(v)
 			case "params":
 				m := cast.ToStringMap(v)
 				// Needed for case insensitive fetching of params values
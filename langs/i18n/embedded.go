@@ -0,0 +1,40 @@
+// Copyright 2019 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"github.com/nicksnyder/go-i18n/i18n/bundle"
+	_errors "github.com/pkg/errors"
+)
+
+// embeddedTranslations are the English texts of the embedded templates,
+// e.g. the Open Graph description of term pages. They are the default for
+// every language, so a project only needs to add the ones it translates.
+const embeddedTranslations = `
+[opengraphTermDescription]
+one = "{{ .Count }} post with the {{ .Taxonomy }} '{{ .Term }}'"
+other = "{{ .Count }} posts with the {{ .Taxonomy }} '{{ .Term }}'"
+`
+
+func addEmbeddedTranslations(bundle *bundle.Bundle, langs []string) error {
+	for _, lang := range langs {
+		if lang == "" {
+			continue
+		}
+		if err := bundle.ParseTranslationFileBytes(lang+".toml", []byte(embeddedTranslations)); err != nil {
+			return _errors.Wrap(err, "failed to load the embedded translations")
+		}
+	}
+	return nil
+}
@@ -53,6 +53,7 @@ func (tp *TranslationProvider) Update(d *deps.Deps) error {
 	// The source dirs are ordered so the most important comes first. Since this is a
 	// last key win situation, we have to reverse the iteration order.
 	dirs := d.BaseFs.I18n.Dirs
+	var dirFiles [][]source.File
 	for i := len(dirs) - 1; i >= 0; i-- {
 		dir := dirs[i]
 		src := spec.NewFilesystemFromFileMetaInfo(dir)
@@ -61,20 +62,35 @@ func (tp *TranslationProvider) Update(d *deps.Deps) error {
 		if err != nil {
 			return err
 		}
+		dirFiles = append(dirFiles, files)
+	}
 
+	// The embedded translations go first, so the project and themes
+	// can override them.
+	embeddedLangs := []string{"en", d.Cfg.GetString("defaultContentLanguage")}
+	for _, files := range dirFiles {
 		for _, r := range files {
-			currentSpec := language.GetPluralSpec(r.BaseFileName())
-			if currentSpec == nil {
-				// This may is a language code not supported by go-i18n, it may be
-				// Klingon or ... not even a fake language. Make sure it works.
-				newLangs = append(newLangs, r.BaseFileName())
-			}
+			embeddedLangs = append(embeddedLangs, r.BaseFileName())
 		}
+	}
 
-		if len(newLangs) > 0 {
-			language.RegisterPluralSpec(newLangs, en)
+	for _, lang := range embeddedLangs {
+		if language.GetPluralSpec(lang) == nil {
+			// This may is a language code not supported by go-i18n, it may be
+			// Klingon or ... not even a fake language. Make sure it works.
+			newLangs = append(newLangs, lang)
 		}
+	}
+
+	if len(newLangs) > 0 {
+		language.RegisterPluralSpec(newLangs, en)
+	}
+
+	if err := addEmbeddedTranslations(i18nBundle, embeddedLangs); err != nil {
+		return err
+	}
 
+	for _, files := range dirFiles {
 		for _, file := range files {
 			if err := addTranslationFile(i18nBundle, file); err != nil {
 				return err
@@ -46,6 +46,10 @@ type Language struct {
 
 	Disabled bool
 
+	// Additional hreflang values for this language, e.g. "pt-br" and "pt-pt"
+	// for "pt". Used for the alternate links in the sitemap.
+	HreflangAliases []string
+
 	// If set per language, this tells Hugo that all content files without any
 	// language indicator (e.g. my-page.en.md) is in this language.
 	// This is usually a path relative to the working dir, but it can be an
@@ -122,6 +126,22 @@ func (l Languages) Less(i, j int) bool {
 
 func (l Languages) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
 
+// Hreflangs returns the hreflang values for this language, its language code
+// followed by any configured aliases, without duplicates.
+func (l *Language) Hreflangs() []string {
+	hreflangs := []string{l.Lang}
+	seen := map[string]bool{strings.ToLower(l.Lang): true}
+	for _, alias := range l.HreflangAliases {
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		hreflangs = append(hreflangs, alias)
+	}
+	return hreflangs
+}
+
 // Params retunrs language-specific params merged with the global params.
 func (l *Language) Params() map[string]interface{} {
 	return l.params
@@ -46,3 +46,16 @@ func TestLanguageParams(t *testing.T) {
 	assert.Equal("p1p", lang.Params()["p1"])
 	assert.Equal("p1cfg", lang.Get("p1"))
 }
+
+func TestLanguageHreflangs(t *testing.T) {
+	assert := require.New(t)
+
+	v := viper.New()
+	v.Set("contentDir", "content")
+
+	lang := NewLanguage("pt", v)
+	assert.Equal([]string{"pt"}, lang.Hreflangs())
+
+	lang.HreflangAliases = []string{"pt-br", "PT", "pt-pt", "pt-BR", ""}
+	assert.Equal([]string{"pt", "pt-br", "pt-pt"}, lang.Hreflangs())
+}
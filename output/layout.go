@@ -71,6 +71,13 @@ func (l *LayoutHandler) For(d LayoutDescriptor, f Format) ([]string, error) {
 	layouts := resolvePageTemplate(d, f)
 
 	layouts = prependTextPrefixIfNeeded(f, layouts...)
+
+	if d.Kind == "taxonomyTerm" && f.Name == JSONFormat.Name {
+		// The term index, e.g. /tags/index.json. As with robots.txt, the
+		// embedded template lives in the HTML template collection.
+		layouts = append(layouts, "_internal/_default/terms.json")
+	}
+
 	layouts = helpers.UniqueStringsReuse(layouts)
 
 	l.mu.Lock()
@@ -104,6 +104,8 @@ func TestLayout(t *testing.T) {
 			[]string{"taxonomy/tag.terms.rss.xml", "taxonomy/terms.rss.xml", "taxonomy/rss.xml", "taxonomy/list.rss.xml", "taxonomy/tag.terms.xml"}, 22},
 		{"Home plain text", LayoutDescriptor{Kind: "home"}, "", JSONFormat,
 			[]string{"_text/index.json.json", "_text/home.json.json"}, 12},
+		{"JSON Taxonomy term", LayoutDescriptor{Kind: "taxonomyTerm", Section: "tag"}, "", JSONFormat,
+			[]string{"_text/taxonomy/tag.terms.json.json", "_text/taxonomy/terms.json.json"}, 19},
 		{"Page plain text", LayoutDescriptor{Kind: "page"}, "", JSONFormat,
 			[]string{"_text/_default/single.json.json", "_text/_default/single.json"}, 2},
 		{"Reserved section, shortcodes", LayoutDescriptor{Kind: "section", Section: "shortcodes", Type: "shortcodes"}, "", ampType,
@@ -135,6 +137,18 @@ func TestLayout(t *testing.T) {
 
 }
 
+func TestLayoutTermsJSONFallback(t *testing.T) {
+	l := NewLayoutHandler()
+
+	layouts, err := l.For(LayoutDescriptor{Kind: "taxonomyTerm", Section: "tag"}, JSONFormat)
+	require.NoError(t, err)
+	require.Equal(t, "_internal/_default/terms.json", layouts[len(layouts)-1])
+
+	layouts, err = l.For(LayoutDescriptor{Kind: "taxonomy", Section: "tag"}, JSONFormat)
+	require.NoError(t, err)
+	require.NotContains(t, layouts, "_internal/_default/terms.json")
+}
+
 func BenchmarkLayout(b *testing.B) {
 	descriptor := LayoutDescriptor{Kind: "taxonomyTerm", Section: "categories"}
 	l := NewLayoutHandler()
@@ -60,7 +60,8 @@ type Descriptor struct {
 	AbsURLPath string
 
 	// Enable to minify the output using the OutputFormat defined above to
-	// pick the correct minifier configuration.
+	// pick the correct minifier configuration. The output is always minified
+	// if minification is enabled for the publisher.
 	Minify bool
 }
 
@@ -74,12 +75,7 @@ type DestinationPublisher struct {
 
 // NewDestinationPublisher creates a new DestinationPublisher.
 func NewDestinationPublisher(fs afero.Fs, outputFormats output.Formats, mediaTypes media.Types, minify bool) DestinationPublisher {
-	pub := DestinationPublisher{fs: fs}
-	if minify {
-		pub.min = minifiers.New(mediaTypes, outputFormats)
-		pub.minify = true
-	}
-	return pub
+	return DestinationPublisher{fs: fs, minify: minify, min: minifiers.New(mediaTypes, outputFormats)}
 }
 
 // Publish applies any relevant transformations and writes the file
@@ -150,7 +146,7 @@ func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Cha
 
 	}
 
-	if p.minify {
+	if p.minify || f.Minify {
 		minifyTransformer := p.min.Transformer(f.OutputFormat.MediaType)
 		if minifyTransformer != nil {
 			transformers = append(transformers, minifyTransformer)
@@ -119,6 +119,9 @@ func (c *imageCache) getOrCreate(
 		}
 		img.relTargetDirFile.file = relTarget.file
 		img.sourceFilename = info.Name
+		// Make sure it's always loaded by sourceFilename, so .Content
+		// is the processed image and not its parent.
+		img.openReadSeekerCloser = nil
 
 		destinations, err := img.openDestinationsForWriting()
 		if err != nil {
@@ -416,6 +416,20 @@ func (l *genericResource) MediaType() media.Type {
 	return l.mediaType
 }
 
+// Size returns the size in bytes of the resource's source file. The content
+// is only read for resources not backed by a file.
+func (l *genericResource) Size() (int64, error) {
+	if l.osFileInfo != nil {
+		return l.osFileInfo.Size(), nil
+	}
+
+	if err := l.initContent(); err != nil {
+		return 0, err
+	}
+
+	return int64(len(l.content)), nil
+}
+
 // Implement the Cloner interface.
 func (l genericResource) WithNewBase(base string) resource.Resource {
 	l.baseOffset = base
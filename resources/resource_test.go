@@ -84,6 +84,22 @@ func TestNewResourceFromFilename(t *testing.T) {
 	assert.Equal("/aceof/a/b/data.json", cloned.RelPermalink())
 }
 
+func TestResourceSize(t *testing.T) {
+	assert := require.New(t)
+	spec := newTestResourceSpec(assert)
+
+	writeSource(t, spec.Fs, "content/a/b/data.json", `{"a": 32}`)
+
+	bfs := afero.NewBasePathFs(spec.Fs.Source, "content")
+
+	r, err := spec.New(ResourceSourceDescriptor{Fs: bfs, SourceFilename: "a/b/data.json"})
+	assert.NoError(err)
+
+	size, err := r.(*genericResource).Size()
+	assert.NoError(err)
+	assert.Equal(int64(9), size)
+}
+
 func TestNewResourceFromFilenameSubPathInBaseURL(t *testing.T) {
 	assert := require.New(t)
 	spec := newTestResourceSpecForBaseURL(assert, "https://example.com/docs")
@@ -17,25 +17,40 @@ package data
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/gohugoio/hugo/config/services"
 	"github.com/gohugoio/hugo/deps"
 	_errors "github.com/pkg/errors"
 )
 
 // New returns a new instance of the data-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
+	var (
+		oembedHosts []string
+		githubToken string
+	)
+	if sc, err := services.DecodeConfig(deps.Cfg); err == nil {
+		oembedHosts = sc.OEmbed.AllowedHosts
+		githubToken = sc.GitHub.Token
+	}
 
 	return &Namespace{
 		deps:         deps,
 		cacheGetCSV:  deps.FileCaches.GetCSVCache(),
 		cacheGetJSON: deps.FileCaches.GetJSONCache(),
-		client:       http.DefaultClient,
+		cacheOEmbed:  deps.FileCaches.OEmbedCache(),
+		oembedHosts:  oembedHosts,
+		githubToken:  githubToken,
+		client:       &http.Client{CheckRedirect: checkRedirect},
 	}
 }
 
@@ -45,6 +60,13 @@ type Namespace struct {
 
 	cacheGetJSON *filecache.Cache
 	cacheGetCSV  *filecache.Cache
+	cacheOEmbed  *filecache.Cache
+
+	// The hosts GetOEmbed may fetch from, all if empty.
+	oembedHosts []string
+
+	// Sent with the GetOEmbed requests to the GitHub API, if set.
+	githubToken string
 
 	client *http.Client
 }
@@ -81,7 +103,7 @@ func (ns *Namespace) GetCSV(sep string, urlParts ...string) (d [][]string, err e
 	req.Header.Add("Accept", "text/csv")
 	req.Header.Add("Accept", "text/plain")
 
-	err = ns.getResource(cache, unmarshal, req)
+	err = ns.getResource(cache, false, unmarshal, req)
 	if err != nil {
 		ns.deps.Log.ERROR.Printf("Failed to get CSV resource %q: %s", url, err)
 		return nil, nil
@@ -94,15 +116,82 @@ func (ns *Namespace) GetCSV(sep string, urlParts ...string) (d [][]string, err e
 // If you provide multiple parts they will be joined together to the final URL.
 // GetJSON returns nil or parsed JSON to use in a short code.
 func (ns *Namespace) GetJSON(urlParts ...string) (interface{}, error) {
-	var v interface{}
 	url := strings.Join(urlParts, "")
-	cache := ns.cacheGetJSON
-
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, _errors.Wrapf(err, "Failed to create request for getJSON resource %s", url)
 	}
 
+	return ns.getJSON(ns.cacheGetJSON, false, ns.deps.Log.ERROR, req)
+}
+
+// GetOEmbed is the same as GetJSON, but it is meant for oEmbed endpoints and
+// similar APIs used by the embedded shortcodes, and uses its own file cache.
+// If fetching fails, e.g. when building offline, any expired data in the
+// cache is used instead. Failures are logged as warnings, leaving it to the
+// caller to handle the nil result.
+// If services.oembed.allowedHosts is set, URLs on other hosts are refused
+// with a warning, and so are redirects to them.
+// If services.github.token is set, it is sent in the Authorization header of
+// requests to the GitHub API, so it never shows up in URLs or log messages.
+func (ns *Namespace) GetOEmbed(urlParts ...string) (interface{}, error) {
+	rawURL := strings.Join(urlParts, "")
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, _errors.Wrapf(err, "failed to parse getOEmbed URL %s", rawURL)
+	}
+	if len(ns.oembedHosts) > 0 && u.Scheme != "" && !isAllowedHost(u.Hostname(), ns.oembedHosts) {
+		ns.deps.Log.WARN.Printf("Refusing to get oEmbed resource %q: host %q is not in services.oembed.allowedHosts", rawURL, u.Hostname())
+		return nil, nil
+	}
+
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, _errors.Wrapf(err, "Failed to create request for getOEmbed resource %s", rawURL)
+	}
+	if len(ns.oembedHosts) > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), allowedHostsKey{}, ns.oembedHosts))
+	}
+	if ns.githubToken != "" && strings.EqualFold(u.Hostname(), "api.github.com") {
+		req.Header.Set("Authorization", "token "+ns.githubToken)
+	}
+
+	return ns.getJSON(ns.cacheOEmbed, true, ns.deps.Log.WARN, req)
+}
+
+// allowedHostsKey is the request context key for the hosts a request may be
+// redirected to.
+type allowedHostsKey struct{}
+
+// checkRedirect is the redirect policy of the HTTP client. It is the default
+// policy, but also refuses redirects to hosts not in the allowed hosts of the
+// request's context, if any.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if allowed, ok := req.Context().Value(allowedHostsKey{}).([]string); ok && !isAllowedHost(req.URL.Hostname(), allowed) {
+		return _errors.Errorf("refusing redirect to %q: host %q is not in services.oembed.allowedHosts", req.URL, req.URL.Hostname())
+	}
+	return nil
+}
+
+// isAllowedHost reports whether host is one of the allowed hosts or a
+// subdomain of one of them.
+func isAllowedHost(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimPrefix(a, "."))
+		if host == a || strings.HasSuffix(host, "."+a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ns *Namespace) getJSON(cache *filecache.Cache, allowStale bool, failureLogger *log.Logger, req *http.Request) (interface{}, error) {
+	var v interface{}
+
 	unmarshal := func(b []byte) (bool, error) {
 		err := json.Unmarshal(b, &v)
 		if err != nil {
@@ -113,9 +202,9 @@ func (ns *Namespace) GetJSON(urlParts ...string) (interface{}, error) {
 
 	req.Header.Add("Accept", "application/json")
 
-	err = ns.getResource(cache, unmarshal, req)
+	err := ns.getResource(cache, allowStale, unmarshal, req)
 	if err != nil {
-		ns.deps.Log.ERROR.Printf("Failed to get JSON resource %q: %s", url, err)
+		failureLogger.Printf("Failed to get JSON resource %q: %s", req.URL, err)
 		return nil, nil
 	}
 
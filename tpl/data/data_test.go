@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -254,3 +255,139 @@ func haveHeader(m http.Header, key, needle string) bool {
 	}
 	return false
 }
+
+func TestGetOEmbedAllowedHosts(t *testing.T) {
+	t.Parallel()
+
+	for i, test := range []struct {
+		allowedHosts []string
+		url          string
+		allowed      bool
+	}{
+		{nil, "http://example.org/oembed", true},
+		{[]string{"twitter.com"}, "http://twitter.com/oembed", true},
+		{[]string{"twitter.com"}, "http://api.Twitter.com:8080/oembed", true},
+		{[]string{"vimeo.com", "twitter.com"}, "http://example.org/oembed", false},
+		{[]string{"twitter.com"}, "http://eviltwitter.com/oembed", false},
+	} {
+		msg := fmt.Sprintf("Test %d", i)
+
+		v := viper.New()
+		v.Set("contentDir", "content")
+		v.Set("services", map[string]interface{}{
+			"oembed": map[string]interface{}{"allowedHosts": test.allowedHosts},
+		})
+		ns := New(newDeps(v))
+
+		var requested bool
+		var srv *httptest.Server
+		srv, ns.client = getTestServer(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+			w.Header().Add("Content-type", "application/json")
+			w.Write([]byte(`{"html":"<p>embed</p>"}`))
+		})
+
+		got, err := ns.GetOEmbed(test.url)
+		srv.Close()
+
+		require.NoError(t, err, msg)
+		require.Equal(t, test.allowed, requested, msg)
+		if test.allowed {
+			require.Equal(t, map[string]interface{}{"html": "<p>embed</p>"}, got, msg)
+			require.Equal(t, 0, int(ns.deps.Log.WarnCounter.Count()), msg)
+		} else {
+			require.Nil(t, got, msg)
+			require.Equal(t, 1, int(ns.deps.Log.WarnCounter.Count()), msg)
+		}
+	}
+}
+
+func TestGetOEmbedAllowedHostsRedirect(t *testing.T) {
+	t.Parallel()
+
+	for i, test := range []struct {
+		location string
+		allowed  bool
+	}{
+		{"http://api.twitter.com/oembed/final", true},
+		{"http://example.org/oembed/final", false},
+	} {
+		msg := fmt.Sprintf("Test %d", i)
+
+		v := viper.New()
+		v.Set("contentDir", "content")
+		v.Set("services", map[string]interface{}{
+			"oembed": map[string]interface{}{"allowedHosts": []string{"twitter.com"}},
+		})
+		ns := New(newDeps(v))
+
+		var requests int
+		var srv *httptest.Server
+		srv, ns.client = getTestServer(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/oembed/final" {
+				http.Redirect(w, r, test.location, http.StatusFound)
+				return
+			}
+			w.Header().Add("Content-type", "application/json")
+			w.Write([]byte(`{"html":"<p>embed</p>"}`))
+		})
+		ns.client.CheckRedirect = checkRedirect
+
+		got, err := ns.GetOEmbed("http://twitter.com/oembed")
+		srv.Close()
+
+		require.NoError(t, err, msg)
+		if test.allowed {
+			require.Equal(t, 2, requests, msg)
+			require.Equal(t, map[string]interface{}{"html": "<p>embed</p>"}, got, msg)
+		} else {
+			// The redirect target is never requested.
+			require.Equal(t, 1, requests, msg)
+			require.Nil(t, got, msg)
+			require.Equal(t, 1, int(ns.deps.Log.WarnCounter.Count()), msg)
+		}
+	}
+}
+
+func TestGetOEmbedGitHubToken(t *testing.T) {
+	t.Parallel()
+
+	for i, test := range []struct {
+		url  string
+		auth string
+	}{
+		{"http://api.github.com/repos/gohugoio/hugo", "token secret"},
+		{"http://api.GitHub.com/repos/gohugoio/hugo", "token secret"},
+		{"http://github.com/gohugoio/hugo", ""},
+		{"http://api.twitter.com/1/statuses/oembed.json?id=1", ""},
+	} {
+		msg := fmt.Sprintf("Test %d", i)
+
+		v := viper.New()
+		v.Set("contentDir", "content")
+		v.Set("services", map[string]interface{}{
+			"github": map[string]interface{}{"token": "secret"},
+		})
+		ns := New(newDeps(v))
+
+		var requested bool
+		var auth, rawQuery string
+		var srv *httptest.Server
+		srv, ns.client = getTestServer(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+			auth = r.Header.Get("Authorization")
+			rawQuery = r.URL.RawQuery
+			w.Header().Add("Content-type", "application/json")
+			w.Write([]byte(`{"full_name":"gohugoio/hugo"}`))
+		})
+
+		_, err := ns.GetOEmbed(test.url)
+		srv.Close()
+
+		require.NoError(t, err, msg)
+		require.True(t, requested, msg)
+		require.Equal(t, test.auth, auth, msg)
+		require.NotContains(t, rawQuery, "secret", msg)
+	}
+}
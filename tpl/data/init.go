@@ -38,6 +38,11 @@ func init() {
 			[]string{"getJSON"},
 			[][2]string{},
 		)
+
+		ns.AddMethodMapping(ctx.GetOEmbed,
+			[]string{"getOEmbed"},
+			[][2]string{},
+		)
 		return ns
 	}
 
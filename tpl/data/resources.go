@@ -34,13 +34,19 @@ var (
 )
 
 // getRemote loads the content of a remote file. This method is thread safe.
-func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (bool, error), req *http.Request) error {
+// If allowStale is set, expired cached content is used if the download fails.
+func (ns *Namespace) getRemote(cache *filecache.Cache, allowStale bool, unmarshal func([]byte) (bool, error), req *http.Request) error {
 	url := req.URL.String()
 	id := helpers.MD5String(url)
 	var handled bool
 	var retry bool
 
-	_, b, err := cache.GetOrCreateBytes(id, func() ([]byte, error) {
+	getOrCreate := cache.GetOrCreateBytes
+	if allowStale {
+		getOrCreate = cache.GetOrCreateBytesOrStale
+	}
+
+	download := func() ([]byte, error) {
 		var err error
 		handled = true
 		for i := 0; i <= resRetries; i++ {
@@ -81,8 +87,20 @@ func (ns *Namespace) getRemote(cache *filecache.Cache, unmarshal func([]byte) (b
 
 		return nil, err
 
+	}
+
+	var downloadErr error
+	_, b, err := getOrCreate(id, func() ([]byte, error) {
+		b, err := download()
+		downloadErr = err
+		return b, err
 	})
 
+	if err == nil && downloadErr != nil {
+		ns.deps.Log.WARN.Printf("Failed to download %s, using expired cached content: %s", url, downloadErr)
+		handled = false
+	}
+
 	if !handled {
 		// This is cached content and should be correct.
 		_, err = unmarshal(b)
@@ -104,7 +122,7 @@ func getLocal(url string, fs afero.Fs, cfg config.Provider) ([]byte, error) {
 
 // getResource loads the content of a local or remote file and returns its content and the
 // cache ID used, if relevant.
-func (ns *Namespace) getResource(cache *filecache.Cache, unmarshal func(b []byte) (bool, error), req *http.Request) error {
+func (ns *Namespace) getResource(cache *filecache.Cache, allowStale bool, unmarshal func(b []byte) (bool, error), req *http.Request) error {
 	switch req.URL.Scheme {
 	case "":
 		b, err := getLocal(req.URL.String(), ns.deps.Fs.Source, ns.deps.Cfg)
@@ -114,7 +132,7 @@ func (ns *Namespace) getResource(cache *filecache.Cache, unmarshal func(b []byte
 		_, err = unmarshal(b)
 		return err
 	default:
-		return ns.getRemote(cache, unmarshal, req)
+		return ns.getRemote(cache, allowStale, unmarshal, req)
 	}
 }
 
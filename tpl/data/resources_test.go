@@ -121,7 +121,7 @@ func TestScpGetRemote(t *testing.T) {
 			return false, nil
 		}
 
-		err = ns.getRemote(cache, f, req)
+		err = ns.getRemote(cache, false, f, req)
 		require.NoError(t, err, msg)
 		assert.Equal(t, string(test.content), string(c))
 
@@ -164,7 +164,7 @@ func TestScpGetRemoteParallel(t *testing.T) {
 						c = b
 						return false, nil
 					}
-					err := ns.getRemote(ns.cacheGetJSON, f, req)
+					err := ns.getRemote(ns.cacheGetJSON, false, f, req)
 
 					assert.NoError(t, err)
 					if string(content) != string(c) {
@@ -34,6 +34,13 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.FormatDate,
+			nil,
+			[][2]string{
+				{`{{ lang.FormatDate "Monday, January 2, 2006" "2015-01-21" }}`, `Wednesday, January 21, 2015`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.NumFmt,
 			nil,
 			[][2]string{
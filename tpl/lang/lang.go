@@ -47,6 +47,69 @@ func (ns *Namespace) Translate(id interface{}, args ...interface{}) (string, err
 	return ns.deps.Translate(sid, args...), nil
 }
 
+// FormatDate converts the textual representation of the datetime string into
+// the other form or returns it of the time.Time value, like dateFormat, but
+// with the month and day names in the layout translated to the current
+// language. The names are looked up in the i18n bundles using the English
+// name as the ID, e.g. "January", "Jan", "Monday" or "Mon". Names without a
+// translation are kept in English.
+func (ns *Namespace) FormatDate(layout string, v interface{}) (string, error) {
+	t, err := cast.ToTimeE(v)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for {
+		i, name := nextDateName(layout)
+		b.WriteString(t.Format(layout[:i]))
+		if name == "" {
+			break
+		}
+		b.WriteString(ns.translateDateName(t.Format(name)))
+		layout = layout[i+len(name):]
+	}
+
+	return b.String(), nil
+}
+
+// nextDateName returns the position and the layout element of the first
+// month or day name in layout, or the length of layout and an empty string
+// if there is none. The other elements that start with or contain an M,
+// "PM" and "MST", are skipped the same way as the time package does.
+func nextDateName(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		s := layout[i:]
+		switch {
+		case strings.HasPrefix(s, "PM"):
+			i++
+		case strings.HasPrefix(s, "MST"):
+			i += 2
+		case strings.HasPrefix(s, "January"):
+			return i, "January"
+		case strings.HasPrefix(s, "Jan"):
+			return i, "Jan"
+		case strings.HasPrefix(s, "Monday"):
+			return i, "Monday"
+		case strings.HasPrefix(s, "Mon"):
+			return i, "Mon"
+		}
+	}
+	return len(layout), ""
+}
+
+func (ns *Namespace) translateDateName(name string) string {
+	if ns.deps.Translate == nil {
+		return name
+	}
+	// A missing translation is empty, or a placeholder with
+	// enableMissingTranslationPlaceholders.
+	if s := ns.deps.Translate(name); s != "" && s != "[i18n] "+name {
+		return s
+	}
+	return name
+}
+
 // NumFmt formats a number with the given precision using the
 // negative, decimal, and grouping options.  The `options`
 // parameter is a string consisting of `<negative> <decimal> <grouping>`.  The
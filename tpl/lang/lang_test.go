@@ -61,3 +61,45 @@ func TestNumFormat(t *testing.T) {
 		assert.Equal(t, c.want, s, errMsg)
 	}
 }
+
+func TestFormatDate(t *testing.T) {
+	t.Parallel()
+
+	translations := map[string]string{
+		"January": "janvier",
+		"Jan":     "janv.",
+		"Monday":  "lundi",
+		"Mon":     "lun.",
+	}
+	ns := New(&deps.Deps{Translate: func(id string, args ...interface{}) string {
+		if s, ok := translations[id]; ok {
+			return s
+		}
+		return "[i18n] " + id
+	}})
+
+	for i, test := range []struct {
+		layout string
+		want   string
+	}{
+		{"Monday 2 January 2006", "lundi 5 janvier 2015"},
+		{"Mon, 02 Jan 2006", "lun., 05 janv. 2015"},
+		{"2006-01-02T15:04:05-07:00", "2015-01-05T13:04:05+00:00"},
+		{"3PM Mon", "1PM lun."},
+		{"MST Jan", "UTC janv."},
+	} {
+		errMsg := fmt.Sprintf("[%d] %s", i, test.layout)
+
+		s, err := ns.FormatDate(test.layout, "2015-01-05T13:04:05Z")
+		require.NoError(t, err, errMsg)
+		assert.Equal(t, test.want, s, errMsg)
+	}
+
+	// No translations for February and Tuesday.
+	s, err := ns.FormatDate("January Mon", "2015-02-03")
+	require.NoError(t, err)
+	assert.Equal(t, "February Tue", s)
+
+	_, err = ns.FormatDate("2006", "not a date")
+	require.Error(t, err)
+}
@@ -170,6 +170,14 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.TruncateWords,
+			nil,
+			[][2]string{
+				{`{{ "this is a very long text" | strings.TruncateWords 3 " ..." }}`, `this is a ...`},
+				{`{{ "With [Markdown](/markdown) inside." | markdownify | strings.TruncateWords 2 }}`, `With <a href="/markdown">Markdown …</a>`},
+			},
+		)
+
 		ns.AddMethodMapping(ctx.Repeat,
 			nil,
 			[][2]string{
@@ -41,6 +41,16 @@ type htmlTag struct {
 
 // Truncate truncates a given string to the specified length.
 func (ns *Namespace) Truncate(a interface{}, options ...interface{}) (template.HTML, error) {
+	return ns.truncate(false, a, options...)
+}
+
+// TruncateWords truncates a given string to the specified number of words.
+// As with Truncate, any HTML tags left open are closed.
+func (ns *Namespace) TruncateWords(a interface{}, options ...interface{}) (template.HTML, error) {
+	return ns.truncate(true, a, options...)
+}
+
+func (ns *Namespace) truncate(countWords bool, a interface{}, options ...interface{}) (template.HTML, error) {
 	length, err := cast.ToIntE(a)
 	if err != nil {
 		return "", err
@@ -76,7 +86,7 @@ func (ns *Namespace) Truncate(a interface{}, options ...interface{}) (template.H
 
 	_, isHTML := textParam.(template.HTML)
 
-	if utf8.RuneCountInString(text) <= length {
+	if !countWords && utf8.RuneCountInString(text) <= length {
 		if isHTML {
 			return template.HTML(text), nil
 		}
@@ -85,6 +95,7 @@ func (ns *Namespace) Truncate(a interface{}, options ...interface{}) (template.H
 
 	tags := []htmlTag{}
 	var lastWordIndex, lastNonSpace, currentLen, endTextPos, nextTag int
+	var inWord bool
 
 	for i, r := range text {
 		if i < nextTag {
@@ -108,17 +119,31 @@ func (ns *Namespace) Truncate(a interface{}, options ...interface{}) (template.H
 			}
 		}
 
-		currentLen++
-		if unicode.IsSpace(r) {
+		isSpace := unicode.IsSpace(r)
+		isCJK := unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana)
+
+		if countWords {
+			// Count the words as they start. CJK characters are words on their own.
+			if !isSpace && (!inWord || isCJK) {
+				currentLen++
+			}
+			inWord = !isSpace && !isCJK
+		} else {
+			currentLen++
+		}
+
+		if isSpace {
 			lastWordIndex = lastNonSpace
-		} else if unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) {
+		} else if isCJK && !countWords {
 			lastWordIndex = i
-		} else {
+		} else if !countWords || currentLen <= length {
 			lastNonSpace = i + utf8.RuneLen(r)
 		}
 
 		if currentLen > length {
-			if lastWordIndex == 0 {
+			if countWords {
+				endTextPos = lastNonSpace
+			} else if lastWordIndex == 0 {
 				endTextPos = i
 			} else {
 				endTextPos = lastWordIndex
@@ -82,3 +82,38 @@ func TestTruncate(t *testing.T) {
 	}
 
 }
+
+func TestTruncateWords(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		v1   interface{}
+		v2   interface{}
+		want template.HTML
+	}{
+		{3, "I am a test sentence", template.HTML("I am a …")},
+		{5, "I am a test sentence", template.HTML("I am a test sentence")},
+		{3, "  I   am\na test", template.HTML("  I   am\na …")},
+		{2, "<b>Should be escaped</b>", template.HTML("&lt;b&gt;Should be …")},
+		{3, template.HTML("I have a <a href='/markdown'>Markdown link</a> inside."), template.HTML("I have a …")},
+		{4, template.HTML("I have a <a href='/markdown'>Markdown link</a> inside."), template.HTML("I have a <a href='/markdown'>Markdown …</a>")},
+		{2, template.HTML("<p>test <b>hel</b>lo</p>\n<p>test something</p>"), template.HTML("<p>test <b>hel</b>lo …</p>")},
+		{3, template.HTML("<p>test <b>hello</b> test something</p>"), template.HTML("<p>test <b>hello</b> test …</p>")},
+		{3, "Hello中国 Good 好的", template.HTML("Hello中国 …")},
+		{5, template.HTML("<p>Hello中国 Good 好的</p>"), template.HTML("<p>Hello中国 Good 好 …</p>")},
+	}
+	for i, c := range cases {
+		result, err := ns.TruncateWords(c.v1, c.v2)
+		if err != nil {
+			t.Errorf("[%d] failed: %s", i, err)
+			continue
+		}
+		if result != c.want {
+			t.Errorf("[%d] got '%s' but expected '%s'", i, result, c.want)
+		}
+	}
+
+	if _, err := ns.TruncateWords(10); err == nil {
+		t.Errorf("Should have errored")
+	}
+}
@@ -18,72 +18,469 @@ package embedded
 
 // EmbeddedTemplates represents all embedded templates.
 var EmbeddedTemplates = [][2]string{
-	{`_default/robots.txt`, `User-agent: *`},
+	{`__breadcrumbs.html`, `{{- define "__breadcrumbs" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the breadcrumb trail of a page, from the home page to the page itself. A taxonomy term page has the taxonomy page as its parent, e.g. Home, Tags, Go; other pages use their section ancestry. Expects a dict with the page and a scratch to store the trail in. */ -}}
+{{- $page := .page -}}
+{{- $trail := slice }}{{ with $page.Site.Home }}{{ $trail = $trail | append . }}{{ end -}}
+{{- if eq $page.Kind "taxonomy" -}}
+{{- with $page.Site.GetPage (printf "/%s" $page.Data.Plural) }}{{ $trail = $trail | append . }}{{ end -}}
+{{- else if ne $page.Kind "taxonomyTerm" -}}
+{{- $ancestors := newScratch }}{{ template "__breadcrumb_ancestors" (dict "page" $page "scratch" $ancestors) -}}
+{{- range $ancestors.Get "ancestors" }}{{ if not .IsHome }}{{ $trail = $trail | append . }}{{ end }}{{ end -}}
+{{- end -}}
+{{- if not $page.IsHome }}{{ $trail = $trail | append $page }}{{ end -}}
+{{- .scratch.Set "trail" $trail -}}
+{{- end -}}
+{{- define "__breadcrumb_ancestors" -}}
+{{- with .page.Parent }}{{ template "__breadcrumb_ancestors" (dict "page" . "scratch" $.scratch) }}{{ $.scratch.Add "ancestors" (slice .) }}{{ end -}}
+{{- end -}}
+`},
+	{`__canonical_url.html`, `{{- define "__canonical_url" -}}{{/* These template definitions are global. */}}
+{{- /* Normalizes the trailing slash of a page URL emitted by the social and schema templates to params.canonicalTrailingSlash: true adds it, false removes it. URLs are left as is if it isn't set, as are the site root and paths ending with a file name, e.g. index.xml. The query and fragment are kept. Expects a dict with the page, the URL and a scratch to store the normalized URL in. */ -}}
+{{- $url := .url -}}
+{{- if isset .page.Site.Params "canonicaltrailingslash" -}}
+{{- $base := replaceRE "[?#].*$" "" $url -}}
+{{- $rest := strings.TrimPrefix $base $url -}}
+{{- $path := (urls.Parse $base).Path -}}
+{{- if and (not (in (slice "" "/") $path)) (not (findRE "\\.[^/]*$" (strings.TrimSuffix "/" $path))) -}}
+{{- $base = strings.TrimSuffix "/" $base -}}
+{{- if index .page.Site.Params "canonicaltrailingslash" }}{{ $base = printf "%s/" $base }}{{ end -}}
+{{- $url = printf "%s%s" $base $rest -}}
+{{- end -}}
+{{- end -}}
+{{- .scratch.Set "url" $url -}}
+{{- end -}}
+`},
+	{`__featured_image.html`, `{{- define "__featured_image" -}}{{/* These template definitions are global. */}}
+{{- /* Finds the featured image of a page bundle: the first image page resource matching one of the params.social.featuredImages globs, tried in order, by default "*feature*" and then "{*cover*,*thumbnail*}". Expects a dict with the page and a scratch to store the resource in. */ -}}
+{{- $globs := slice "*feature*" "{*cover*,*thumbnail*}" -}}
+{{- with .page.Site.Params.social }}{{ with index . "featuredimages" }}{{ $globs = cond (reflect.IsSlice .) . (slice .) }}{{ end }}{{ end -}}
+{{- $resources := .page.Resources.ByType "image" -}}
+{{- range $globs -}}
+{{- if not ($.scratch.Get "image") }}{{ with $resources.GetMatch . }}{{ $.scratch.Set "image" . }}{{ end }}{{ end -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`__image_loading.html`, `{{- define "__image_loading" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the loading attribute of the images emitted by the shortcodes: the shortcode's loading parameter, else params.images.loading, else lazy. Expects a dict with the shortcode and a scratch to store the value in. */ -}}
+{{- $loading := "lazy" -}}
+{{- with .shortcode.Page.Site.Params.images }}{{ if reflect.IsMap . }}{{ with index . "loading" }}{{ $loading = lower . }}{{ end }}{{ end }}{{ end -}}
+{{- with .shortcode.Get "loading" }}{{ $loading = lower . }}{{ end -}}
+{{- if not (in (slice "lazy" "eager") $loading) -}}
+{{- errorf "The %q shortcode loading must be lazy or eager, got %q: %s" .shortcode.Name $loading .shortcode.Position -}}
+{{- end -}}
+{{- .scratch.Set "loading" $loading -}}
+{{- end -}}
+`},
+	{`__image_placeholder.html`, `{{- define "__image_placeholder" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the low quality image placeholder (LQIP) of the images emitted by the shortcodes: the shortcode's placeholder parameter, else params.images.placeholder, else none. With blur, a tiny copy of the image is inlined as the background; with svg, the tiny copy is wrapped in a blurred inline SVG. Expects a dict with the shortcode, an optional image resource and a scratch to store the background style in. */ -}}
+{{- $placeholder := "none" -}}
+{{- with .shortcode.Page.Site.Params.images }}{{ if reflect.IsMap . }}{{ with index . "placeholder" }}{{ $placeholder = lower . }}{{ end }}{{ end }}{{ end -}}
+{{- with .shortcode.Get "placeholder" }}{{ $placeholder = lower . }}{{ end -}}
+{{- if not (in (slice "none" "blur" "svg") $placeholder) -}}
+{{- errorf "The %q shortcode placeholder must be none, blur or svg, got %q: %s" .shortcode.Name $placeholder .shortcode.Position -}}
+{{- end -}}
+{{- if ne $placeholder "none" }}{{ with .image -}}
+{{- $tiny := .Resize "20x q20" -}}
+{{- /* Hugo's JPEG media type is image/jpg. */ -}}
+{{- $data := printf "data:%s;base64,%s" (replace $tiny.MediaType.Type "/jpg" "/jpeg") ($tiny.Content | base64Encode) -}}
+{{- if eq $placeholder "svg" -}}
+{{- $svg := printf "<svg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 %d %d'><filter id='b'><feGaussianBlur stdDeviation='1'/></filter><image width='100%%' height='100%%' preserveAspectRatio='none' filter='url(#b)' href='%s'/></svg>" $tiny.Width $tiny.Height $data -}}
+{{- $data = printf "data:image/svg+xml;base64,%s" ($svg | base64Encode) -}}
+{{- end -}}
+{{- $.scratch.Set "placeholder" (printf "background-image:url(%s);background-size:cover" $data) -}}
+{{- end }}{{ end -}}
+{{- end -}}
+`},
+	{`__image_srcset.html`, `{{- define "__image_srcset" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the responsive image widths from params.images: srcsetWidths, 480, 768 and 1200 by default, and allowUpscale. Expects a dict with the page, an optional image resource to build the srcset for and a scratch to store the sorted widths and the srcset in. Widths larger than the image are skipped unless allowUpscale is set. */ -}}
+{{- $widths := slice 480 768 1200 -}}
+{{- $allowUpscale := false -}}
+{{- with .page.Site.Params.images }}{{ if reflect.IsMap . -}}
+{{- with index . "srcsetwidths" }}{{ $widths = slice }}{{ range . }}{{ $widths = $widths | append (int .) }}{{ end }}{{ end -}}
+{{- with index . "allowupscale" }}{{ $allowUpscale = . }}{{ end -}}
+{{- end }}{{ end -}}
+{{- $widths = sort $widths -}}
+{{- .scratch.Set "widths" $widths -}}
+{{- with .image -}}
+{{- $srcset := slice -}}
+{{- range $widths -}}
+{{- if or $allowUpscale (le . $.image.Width) -}}
+{{- $srcset = $srcset | append (printf "%s %dw" ($.image.Resize (printf "%dx" .)).RelPermalink .) -}}
+{{- end -}}
+{{- end -}}
+{{- $.scratch.Set "srcset" (delimit $srcset ", ") -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`__schema_keywords.html`, `{{- define "__schema_keywords" -}}{{/* These template definitions are global. */}}
+{{- /* Joins the page's tags, or all the site's taxonomy terms for a list page, into schema.org keywords. Expects a dict with the page and a scratch to store the trimmed, non-empty tags, the keywords and whether to emit empty tags (params.social.emitEmptyTags) in; no keywords are stored if there are no tags. */ -}}
+{{- $separator := "," }}{{ $prefix := "" -}}
+{{- with .page.Site.Params.schema -}}
+{{- with index . "keywordsseparator" }}{{ $separator = . }}{{ end -}}
+{{- with index . "keywordsprefix" }}{{ $prefix = . }}{{ end -}}
+{{- end -}}
+{{- $tags := slice -}}
+{{- if .page.IsPage -}}
+{{- with .page.Params.tags }}{{ range cond (reflect.IsSlice .) . (slice .) }}{{ with trim (string .) " " }}{{ $tags = $tags | append . }}{{ end }}{{ end }}{{ end -}}
+{{- else -}}
+{{- range $plural, $terms := .page.Site.Taxonomies }}{{ range $term, $_ := $terms }}{{ if not (in $tags $term) }}{{ $tags = $tags | append $term }}{{ end }}{{ end }}{{ end -}}
+{{- end -}}
+{{- .scratch.Set "tags" $tags -}}
+{{- $emitEmpty := false }}{{ with .page.Site.Params.social }}{{ with index . "emitemptytags" }}{{ $emitEmpty = . }}{{ end }}{{ end -}}
+{{- .scratch.Set "emitEmpty" $emitEmpty -}}
+{{- $keywords := slice -}}
+{{- range $tags }}{{ $keywords = $keywords | append (print $prefix .) }}{{ end -}}
+{{- with $keywords }}{{ $.scratch.Set "keywords" (delimit . $separator) }}{{ end -}}
+{{- end -}}
+`},
+	{`__schema_type.html`, `{{- define "__schema_type" -}}{{/* These template definitions are global. */}}
+{{- /* Selects the schema.org type of a page from params.schema.types and the schema.type front matter, Article by default. Expects a dict with the page and a scratch to store the type in. The type isn't validated here, as schema.html accepts any type; schema_article.html only accepts the article types. */ -}}
+{{- $type := "Article" -}}
+{{- with .page.Site.Params.schema }}{{ with index . "types" }}{{ with index . $.page.Section }}{{ $type = . }}{{ end }}{{ end }}{{ end -}}
+{{- with .page.Params.schema }}{{ with index . "type" }}{{ $type = . }}{{ end }}{{ end -}}
+{{- .scratch.Set "type" $type -}}
+{{- end -}}
+`},
+	{`__social_description.html`, `{{- define "__social_description" -}}{{/* These template definitions are global. */}}
+{{- /* Builds the plain text description of the social meta tags: the page's description, its summary for regular pages and the site's description for others. Expects a dict with the page, the length to truncate the description to (not truncated if 0 or less), a scratch to store the description in and, optionally, a description to use instead of the page's. */ -}}
+{{- $description := "" -}}
+{{- with .description }}{{ $description = . }}{{ else }}{{ with .page.Description }}{{ $description = . }}{{ else }}{{ if $.page.IsPage }}{{ $description = $.page.Summary }}{{ else }}{{ with $.page.Site.Params.description }}{{ $description = . }}{{ end }}{{ end }}{{ end }}{{ end -}}
+{{- $description = trim ($description | plainify | htmlUnescape | replaceRE "\\s+" " ") " " -}}
+{{- if gt .length 0 }}{{ $description = truncate .length $description }}{{ end -}}
+{{- .scratch.Set "description" $description -}}
+{{- end -}}
+`},
+	{`__social_image.html`, `{{- define "__social_image" -}}{{/* These template definitions are global. */}}
+{{- /* Processes an image page resource for the social templates. Expects a dict with the page, the image path, a scratch to store the processed image in and an optional aspect ratio. The width defaults to the largest of the srcset widths. */ -}}
+{{- $process := false -}}
+{{- $srcset := newScratch }}{{ template "__image_srcset" (dict "page" .page "scratch" $srcset) -}}
+{{- $widths := $srcset.Get "widths" -}}
+{{- $width := 1200 }}{{ with $widths }}{{ $width = index . (sub (len .) 1) }}{{ end -}}
+{{- $height := 0 }}{{ $fit := "fill" }}{{ $quality := 0 -}}
+{{- with .page.Site.Params.social }}{{ with index . "image" -}}
+{{- $process = true }}{{ $quality = 80 -}}
+{{- with index . "width" }}{{ $width = int . }}{{ end -}}
+{{- with index . "height" }}{{ $height = int . }}{{ end -}}
+{{- with index . "fit" }}{{ $fit = lower . }}{{ end -}}
+{{- with index . "quality" }}{{ $quality = int . }}{{ end -}}
+{{- end }}{{ end -}}
+{{- /* Without a configured height, keep the 1200x630 aspect ratio for the width. */ -}}
+{{- if not $height }}{{ $height = int (div (mul (float $width) 630) 1200) }}{{ end -}}
+{{- with .aspect -}}
+{{- $aspect := 0.0 -}}
+{{- if findRE "^[0-9]*\\.?[0-9]+(:[0-9]*\\.?[0-9]+)?$" (string .) -}}
+{{- $ratio := split (string .) ":" }}{{ $aspect = float (index $ratio 0) -}}
+{{- if gt (len $ratio) 1 }}{{ $d := float (index $ratio 1) }}{{ if gt $d 0.0 }}{{ $aspect = div $aspect $d }}{{ else }}{{ $aspect = 0.0 }}{{ end }}{{ end -}}
+{{- end -}}
+{{- if gt $aspect 0.0 -}}
+{{- $process = true -}}
+{{- $height = int (div (float $width) $aspect) -}}
+{{- else -}}
+{{- warnf "params.opengraph.imageAspect must be a positive number or ratio, e.g. 1.91:1, got %q; ignoring it" (string .) -}}
+{{- end -}}
+{{- end -}}
+{{- if not (in (slice "fill" "fit" "resize") $fit) }}{{ errorf "params.social.image.fit must be one of fill, fit or resize, got %q" $fit }}{{ end -}}
+{{- if and $process (not (findRE "^(https?:)?//" .path)) -}}
+{{- with .page.Resources.GetMatch .path }}{{ if eq .ResourceType "image" -}}
+{{- $spec := printf "%dx%d" $width $height -}}
+{{- with $quality }}{{ $spec = printf "%s q%d" $spec . }}{{ end -}}
+{{- if eq $fit "fill" }}{{ $.scratch.Set "image" (.Fill (printf "%s Center" $spec)) -}}
+{{- else if eq $fit "fit" }}{{ $.scratch.Set "image" (.Fit $spec) -}}
+{{- else }}{{ $.scratch.Set "image" (.Resize $spec) }}{{ end -}}
+{{- end }}{{ end -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`__tweet_id.html`, `{{- define "__tweet_id" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the tweet ID from the first shortcode parameter, either the ID itself or a tweet URL, e.g. https://twitter.com/user/status/123. Expects a dict with the shortcode and a scratch to store the ID in. */ -}}
+{{- $id := "" -}}
+{{- with .shortcode.Get 0 }}{{ $id = trim (string .) " " }}{{ end -}}
+{{- if findRE "^(https?://)?((www|mobile)\\.)?(twitter|x)\\.com/" $id -}}
+{{- $id = replaceRE "^(?:https?://)?(?:(?:www|mobile)\\.)?(?:twitter|x)\\.com/(?:\\w+|i(?:/web)?)/status(?:es)?/(\\d+)(?:[/?#].*)?$" "$1" $id -}}
+{{- end -}}
+{{- if not (findRE "^\\d+$" $id) -}}
+{{- errorf "The %q shortcode requires a tweet ID or URL, got %q: %s" .shortcode.Name $id .shortcode.Position -}}
+{{- end -}}
+{{- .scratch.Set "id" $id -}}
+{{- end -}}
+`},
+	{`_default/robots.txt`, `User-agent: *
+{{- with .Site.Config.Robots.CrawlDelay }}
+Crawl-delay: {{ . }}
+{{- end }}
+{{- range .Data.Pages }}
+{{- $robots := .Params.robots }}
+{{- if reflect.IsMap $robots }}{{ if index $robots "disallow" }}
+Disallow: {{ .RelPermalink }}
+{{- end }}{{ end }}
+{{- end }}
+{{- range .Site.Config.Robots.Rules }}
+
+User-agent: {{ .UserAgent }}
+{{- with .CrawlDelay }}
+Crawl-delay: {{ . }}
+{{- end }}
+{{- range .Allow }}
+Allow: {{ . }}
+{{- end }}
+{{- range .Disallow }}
+Disallow: {{ . }}
+{{- end }}
+{{- end }}
+{{- with .Data.Sitemap }}
+
+Sitemap: {{ . }}
+{{- end }}
+`},
 	{`_default/rss.xml`, `{{- $pages := .Data.Pages -}}
+{{- $rss := .Site.Config.Services.RSS -}}
+{{- if not (and $rss.IncludeDrafts $rss.IncludeFuture $rss.IncludeExpired) -}}
+{{- $included := slice -}}
+{{- range $pages -}}
+{{- $include := true -}}
+{{- if and .Draft (not $rss.IncludeDrafts) }}{{ $include = false }}{{ end -}}
+{{- if and (not $rss.IncludeFuture) (not .PublishDate.IsZero) (.PublishDate.After now) }}{{ $include = false }}{{ end -}}
+{{- if and (not $rss.IncludeExpired) (not .ExpiryDate.IsZero) (.ExpiryDate.Before now) }}{{ $include = false }}{{ end -}}
+{{- if $include }}{{ $included = $included | append . }}{{ end -}}
+{{- end -}}
+{{- $pages = $included -}}
+{{- end -}}
 {{- $limit := .Site.Config.Services.RSS.Limit -}}
+{{- /* The configured title is the home page feed's, the other feeds keep the "X on Site" default. */ -}}
+{{- $title := cond .IsHome .Site.Config.Services.RSS.Title "" -}}
+{{- with .Params.rss }}{{ if isset . "limit" }}{{ $limit = int (index . "limit") }}{{ end }}{{ with index . "title" }}{{ $title = . }}{{ end }}{{ end -}}
 {{- if ge $limit 1 -}}
 {{- $pages = $pages | first $limit -}}
 {{- end -}}
+{{- $summaryLength := .Site.Config.Services.RSS.SummaryLength -}}
+{{- $manualSummary := .Site.Config.Services.RSS.ManualSummary -}}
+{{- $fullContent := .Site.Config.Services.RSS.FullContent -}}
+{{- $absolutizeURLs := .Site.Config.Services.RSS.AbsolutizeURLs -}}
+{{- $itemPartial := templates.Exists "partials/rss-item.html" -}}
+{{- $stableGUID := eq (lower .Site.Config.Services.RSS.GUID) "stable" -}}
+{{- $guidPrefix := .Site.Config.Services.RSS.GUIDPrefix -}}
+{{- /* The default RFC 822 layout keeps the English names; a configured layout gets the names of the site language. */ -}}
+{{- $localizeDates := ne .Site.Config.Services.RSS.DateFormat "" -}}
+{{- $dateFormat := .Site.Config.Services.RSS.DateFormat | default "Mon, 02 Jan 2006 15:04:05 -0700" -}}
+{{- $commentsAnchor := .Site.Config.Services.RSS.CommentsAnchor | default (cond (ne .Site.Config.Services.Disqus.Shortname "") "#disqus_thread" "") -}}
+{{- with $commentsAnchor -}}
+{{- $commentsAnchor = printf "#%s" (strings.TrimPrefix "#" .) -}}
+{{- end -}}
+{{- $skipHours := slice -}}
+{{- range .Site.Config.Services.RSS.SkipHours -}}
+{{- if and (ge . 0) (le . 23) }}{{ $skipHours = $skipHours | append . }}{{ else }}{{ warnf "Invalid RSS skipHours value %d, must be in the range 0-23" . }}{{ end -}}
+{{- end -}}
+{{- $skipDays := slice -}}
+{{- $days := dict "monday" "Monday" "tuesday" "Tuesday" "wednesday" "Wednesday" "thursday" "Thursday" "friday" "Friday" "saturday" "Saturday" "sunday" "Sunday" -}}
+{{- range .Site.Config.Services.RSS.SkipDays -}}
+{{- with index $days (lower .) }}{{ $skipDays = $skipDays | append . }}{{ else }}{{ warnf "Invalid RSS skipDays value %q, must be a day name, e.g. Saturday" . }}{{ end -}}
+{{- end -}}
+{{- $languageCode := .Site.LanguageCode -}}
+{{- if not $languageCode -}}
+{{- $lang := .Site.Language.Lang -}}
+{{- $languageCode = index .Site.Config.Services.RSS.LanguageCodes (lower $lang) | default $lang -}}
+{{- end -}}
+{{- $commentsCount := false -}}
+{{- if $commentsAnchor -}}
+{{- range $pages -}}
+{{- if and (ne .Params.comments false) (isset .Params "commentscount") -}}
+{{- $commentsCount = true -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- $dcCreator := false -}}
+{{- range $pages -}}
+{{- $creator := newScratch }}{{ template "__rss_creator" (dict "page" . "scratch" $creator) -}}
+{{- with $creator.Get "creator" }}{{ $dcCreator = true }}{{ end -}}
+{{- end -}}
+{{- /* The current output format is the one that isn't an alternative, so the self link is right if the RSS output format is renamed. Sibling feeds are linked as alternates. */ -}}
+{{- $alternatives := slice }}{{ $feeds := slice -}}
+{{- range .AlternativeOutputFormats -}}
+{{- $alternatives = $alternatives | append .Name -}}
+{{- if in (slice "application/rss+xml" "application/atom+xml" "application/feed+json") .MediaType.Type }}{{ $feeds = $feeds | append . }}{{ end -}}
+{{- end -}}
+{{- $self := .OutputFormats.Get "RSS" -}}
+{{- range .OutputFormats }}{{ if not (or (in $alternatives .Name) .Format.NotAlternative) }}{{ $self = . }}{{ end }}{{ end -}}
 {{- printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\" ?>" | safeHTML }}
-<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"{{ if $commentsCount }} xmlns:slash="http://purl.org/rss/1.0/modules/slash/"{{ end }}{{ if $dcCreator }} xmlns:dc="http://purl.org/dc/elements/1.1/"{{ end }}{{ if $fullContent }} xmlns:content="http://purl.org/rss/1.0/modules/content/"{{ end }}>
   <channel>
-    <title>{{ if eq  .Title  .Site.Title }}{{ .Site.Title }}{{ else }}{{ with .Title }}{{.}} on {{ end }}{{ .Site.Title }}{{ end }}</title>
+    <title>{{ with $title }}{{ . }}{{ else }}{{ if eq  .Title  .Site.Title }}{{ .Site.Title }}{{ else }}{{ with .Title }}{{.}} on {{ end }}{{ .Site.Title }}{{ end }}{{ end }}</title>
     <link>{{ .Permalink }}</link>
     <description>Recent content {{ if ne  .Title  .Site.Title }}{{ with .Title }}in {{.}} {{ end }}{{ end }}on {{ .Site.Title }}</description>
-    <generator>Hugo -- gohugo.io</generator>{{ with .Site.LanguageCode }}
-    <language>{{.}}</language>{{end}}{{ with .Site.Author.email }}
-    <managingEditor>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</managingEditor>{{end}}{{ with .Site.Author.email }}
-    <webMaster>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</webMaster>{{end}}{{ with .Site.Copyright }}
-    <copyright>{{.}}</copyright>{{end}}{{ if not .Date.IsZero }}
-    <lastBuildDate>{{ .Date.Format "Mon, 02 Jan 2006 15:04:05 -0700" | safeHTML }}</lastBuildDate>{{ end }}
-    {{ with .OutputFormats.Get "RSS" }}
-	{{ printf "<atom:link href=%q rel=\"self\" type=%q />" .Permalink .MediaType | safeHTML }}
-    {{ end }}
-    {{ range $pages }}
+    <generator>Hugo -- gohugo.io</generator>
+    {{- with $languageCode }}
+    <language>{{.}}</language>
+    {{- end }}
+    {{- with .Site.Author.email }}
+    <managingEditor>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</managingEditor>
+    <webMaster>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</webMaster>
+    {{- end }}
+    {{- with .Site.Copyright }}
+    <copyright>{{.}}</copyright>
+    {{- end }}
+    {{- if not .Date.IsZero }}
+    <lastBuildDate>{{ cond $localizeDates (lang.FormatDate $dateFormat .Date) (dateFormat $dateFormat .Date) | safeHTML }}</lastBuildDate>
+    {{- end }}
+    {{- with .Site.Config.Services.RSS.TTL }}{{ if gt . 0 }}
+    <ttl>{{ . }}</ttl>
+    {{- end }}{{ end }}
+    {{- with $skipHours }}
+    <skipHours>
+      {{- range . }}
+      <hour>{{ . }}</hour>
+      {{- end }}
+    </skipHours>
+    {{- end }}
+    {{- with $skipDays }}
+    <skipDays>
+      {{- range . }}
+      <day>{{ . }}</day>
+      {{- end }}
+    </skipDays>
+    {{- end }}
+    {{- with $self }}
+    {{ printf "<atom:link href=%q rel=\"self\" type=%q />" .Permalink .MediaType | safeHTML }}
+    {{- end }}
+    {{- range $feeds }}
+    {{ printf "<atom:link href=%q rel=\"alternate\" type=%q />" .Permalink .MediaType | safeHTML }}
+    {{- end }}
+    {{- range $pages }}
     <item>
       <title>{{ .Title }}</title>
       <link>{{ .Permalink }}</link>
-      <pubDate>{{ .Date.Format "Mon, 02 Jan 2006 15:04:05 -0700" | safeHTML }}</pubDate>
-      {{ with .Site.Author.email }}<author>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</author>{{end}}
+      <pubDate>{{ cond $localizeDates (lang.FormatDate $dateFormat .Date) (dateFormat $dateFormat .Date) | safeHTML }}</pubDate>
+      {{- with .Site.Author.email }}
+      <author>{{.}}{{ with $.Site.Author.name }} ({{.}}){{end}}</author>
+      {{- end }}
+      {{- $creator := newScratch }}{{ template "__rss_creator" (dict "page" . "scratch" $creator) }}
+      {{- with $creator.Get "creator" }}
+      <dc:creator>{{ . }}</dc:creator>
+      {{- end }}
+      {{- $guid := "" }}
+      {{- if $stableGUID }}{{ with .Params.guid }}{{ $guid = . }}{{ else }}{{ with .File }}{{ $guid = printf "%s%s" $guidPrefix .UniqueID }}{{ end }}{{ end }}{{ end }}
+      {{- with $guid }}
+      <guid isPermaLink="false">{{ . }}</guid>
+      {{- else }}
       <guid>{{ .Permalink }}</guid>
-      <description>{{ .Summary | html }}</description>
+      {{- end }}
+      {{- $description := "" }}
+      {{- if $itemPartial }}{{ $description = partial "rss-item.html" . }}
+      {{- else if and $manualSummary (or (in .RawContent "<!--more-->") (isset .Params "summary")) }}{{ $description = printf "<div class=\"summary-manual\">%s</div>" .Summary }}
+      {{- else }}
+      {{- if ge $summaryLength 1 }}{{ $description = .Content | strings.TruncateWords $summaryLength }}{{ else }}{{ $description = .Summary }}{{ end }}
+      {{- if $manualSummary }}{{ $description = printf "<div class=\"summary-auto\">%s</div>" $description }}{{ end }}
+      {{- end }}
+      <description>{{ $description | html }}</description>
+      {{- if $fullContent }}
+      {{- $content := .Content }}{{ if and $absolutizeURLs (urls.Parse .Permalink).IsAbs }}{{ $content = urls.ResolveURLs .Permalink $content }}{{ end }}
+      <content:encoded>{{ $content | html }}</content:encoded>
+      {{- end }}
+      {{- if and $commentsAnchor (ne .Params.comments false) }}
+      <comments>{{ .Permalink }}{{ $commentsAnchor }}</comments>
+      {{- if isset .Params "commentscount" }}
+      <slash:comments>{{ .Params.commentsCount }}</slash:comments>
+      {{- end }}
+      {{- end }}
     </item>
-    {{ end }}
+    {{- end }}
   </channel>
-</rss>`},
-	{`_default/sitemap.xml`, `{{ printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\" ?>" | safeHTML }}
+</rss>
+{{ define "__rss_creator" -}}{{/* These template definitions are global. */}}
+{{- /* Joins the names of the page's authors for the dc:creator. Expects a dict with the page and a scratch to store the creator in. The author front matter can be a name, a map with a name or a list of either; the site author's name is used if it gives no names. */ -}}
+{{- $names := slice -}}
+{{- with .page.Params.author -}}
+{{- range cond (reflect.IsSlice .) . (slice .) -}}
+{{- $name := . }}{{ if reflect.IsMap . }}{{ $name = index . "name" }}{{ end -}}
+{{- with trim (string ($name | default "")) " " }}{{ $names = $names | append . }}{{ end -}}
+{{- end -}}
+{{- end -}}
+{{- $creator := .page.Site.Author.name | default "" -}}
+{{- with $names }}{{ $creator = delimit . ", " | string }}{{ end -}}
+{{- .scratch.Set "creator" $creator -}}
+{{- end -}}`},
+	{`_default/sitemap.xml`, `{{- printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\" ?>" | safeHTML }}
 <urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
   xmlns:xhtml="http://www.w3.org/1999/xhtml">
-  {{ range .Data.Pages }}
+  {{- range .Data.Pages }}
+  {{- $page := . }}
+  {{- $locs := slice .Permalink }}
+  {{- with .Sitemap.OutputFormats }}{{ $locs = slice }}{{ range $page.OutputFormats }}{{ if in $page.Sitemap.OutputFormats (lower .Name) }}{{ $locs = $locs | append .Permalink }}{{ end }}{{ end }}{{ end }}
+  {{- range $loc := $locs }}
   <url>
-    <loc>{{ .Permalink }}</loc>{{ if not .Lastmod.IsZero }}
-    <lastmod>{{ safeHTML ( .Lastmod.Format "2006-01-02T15:04:05-07:00" ) }}</lastmod>{{ end }}{{ with .Sitemap.ChangeFreq }}
-    <changefreq>{{ . }}</changefreq>{{ end }}{{ if ge .Sitemap.Priority 0.0 }}
-    <priority>{{ .Sitemap.Priority }}</priority>{{ end }}{{ if .IsTranslated }}{{ range .Translations }}
+    <loc>{{ $loc }}</loc>
+    {{- with $page }}
+    {{- $lastmod := .Lastmod }}
+    {{- $source := lower (.Sitemap.LastmodSource | default "lastmod") }}
+    {{- if eq $source "date" }}{{ $lastmod = .Date }}
+    {{- else if eq $source "publishdate" }}{{ $lastmod = .PublishDate }}
+    {{- else if eq $source "git" }}{{ $lastmod = "" }}{{ with .GitInfo }}{{ $lastmod = .AuthorDate }}{{ end }}
+    {{- else if ne $source "lastmod" }}{{ errorf "sitemap.lastmodSource must be one of lastmod, date, publishDate or git, got %q" .Sitemap.LastmodSource }}
+    {{- end }}
+    {{- $dateFormat := .Sitemap.DateFormat | default "2006-01-02T15:04:05-07:00" }}
+    {{- with $lastmod }}{{ if not .IsZero }}
+    <lastmod>{{ safeHTML ( lang.FormatDate $dateFormat . ) }}</lastmod>
+    {{- end }}{{ end }}
+    {{- with .Sitemap.ChangeFreq }}
+    <changefreq>{{ . }}</changefreq>
+    {{- end }}
+    {{- if ge .Sitemap.Priority 0.0 }}
+    <priority>{{ .Sitemap.Priority }}</priority>
+    {{- end }}
+    {{- if and .IsTranslated (eq $loc .Permalink) }}
+    {{- range .Translations }}{{ $href := .Permalink }}{{ range .Language.Hreflangs }}
     <xhtml:link
                 rel="alternate"
-                hreflang="{{ .Language.Lang }}"
-                href="{{ .Permalink }}"
-                />{{ end }}
+                hreflang="{{ . }}"
+                href="{{ $href }}"
+                />
+    {{- end }}{{ end }}
+    {{- $href := .Permalink }}{{ range .Language.Hreflangs }}
     <xhtml:link
                 rel="alternate"
-                hreflang="{{ .Language.Lang }}"
-                href="{{ .Permalink }}"
-                />{{ end }}
+                hreflang="{{ . }}"
+                href="{{ $href }}"
+                />
+    {{- end }}
+    {{- end }}
+    {{- end }}
   </url>
-  {{ end }}
-</urlset>`},
-	{`_default/sitemapindex.xml`, `{{ printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\" ?>" | safeHTML }}
+  {{- end }}
+  {{- end }}
+</urlset>
+`},
+	{`_default/sitemapindex.xml`, `{{- printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\" ?>" | safeHTML }}
 <sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
-	{{ range . }}
-	<sitemap>
-	   	<loc>{{ .SitemapAbsURL }}</loc>
-		{{ if not .LastChange.IsZero }}
-	   	<lastmod>{{ .LastChange.Format "2006-01-02T15:04:05-07:00" | safeHTML }}</lastmod>
-		{{ end }}
-	</sitemap>
-	{{ end }}
+  {{- range . }}
+  <sitemap>
+    <loc>{{ .SitemapAbsURL }}</loc>
+    {{- if not .LastChange.IsZero }}
+    <lastmod>{{ .LastChange.Format "2006-01-02T15:04:05-07:00" | safeHTML }}</lastmod>
+    {{- end }}
+  </sitemap>
+  {{- end }}
 </sitemapindex>
+`},
+	{`_default/terms.json`, `{{- $terms := slice -}}
+{{- range $entry := .Data.Terms.Alphabetical -}}
+{{- with $.Site.GetPage (printf "/%s/%s" $.Data.Plural $entry.Name) -}}
+{{- $terms = $terms | append (dict "name" $entry.Name "title" .Title "count" $entry.Count "permalink" .Permalink) -}}
+{{- end -}}
+{{- end -}}
+{{- dict "taxonomy" .Data.Plural "terms" $terms | jsonify -}}
+`},
+	{`amp_links.html`, `{{- with .OutputFormats.Get "amp" -}}
+{{- if $.AlternativeOutputFormats.Get "amp" -}}
+{{- if and ($.OutputFormats.Get "html") (not ($.AlternativeOutputFormats.Get "html")) }}
+<link rel="amphtml" href="{{ .Permalink }}" />
+{{- end -}}
+{{- else -}}
+{{- with $.OutputFormats.Get "html" }}
+{{- $canonical := newScratch }}{{ template "__canonical_url" (dict "page" $ "url" .Permalink "scratch" $canonical) }}
+<link rel="canonical" href="{{ $canonical.Get "url" }}" />
+{{- end -}}
+{{- end -}}
+{{- end -}}
 `},
 	{`disqus.html`, `{{- $pc := .Site.Config.Privacy.Disqus -}}
 {{- if not $pc.Disable -}}
@@ -111,6 +508,9 @@ var EmbeddedTemplates = [][2]string{
 	{`google_analytics.html`, `{{- $pc := .Site.Config.Privacy.GoogleAnalytics -}}
 {{- if not $pc.Disable -}}
 {{ with .Site.GoogleAnalytics }}
+{{- if hasPrefix . "G-" }}
+{{ template "__ga4" $ }}
+{{- else }}
 <script type="application/javascript">
 {{ template "__ga_js_set_doNotTrack" $ }}
 if (!doNotTrack) {
@@ -136,6 +536,7 @@ if (!doNotTrack) {
 	ga('send', 'pageview');
 }
 </script>
+{{- end }}
 {{ end }}
 {{- end -}}
 {{- define "__ga_js_set_doNotTrack" -}}{{/* This is also used in the async version. */}}
@@ -146,10 +547,54 @@ var doNotTrack = false;
 var dnt = (navigator.doNotTrack || window.doNotTrack || navigator.msDoNotTrack);
 var doNotTrack = (dnt == "1" || dnt == "yes");
 {{- end -}}
-{{- end -}}`},
+{{- end -}}
+{{- define "__ga4" -}}{{/* Google Analytics 4, used by both the regular and the async version. */}}
+{{- $pc := .Site.Config.Privacy.GoogleAnalytics -}}
+<script type="application/javascript">
+window.dataLayer = window.dataLayer || [];
+function gtag(){dataLayer.push(arguments);}
+{{- if $pc.ConsentMode }}
+gtag('consent', 'default', {
+	'analytics_storage': 'denied',
+	'ad_storage': 'denied',
+	'ad_user_data': 'denied',
+	'ad_personalization': 'denied'
+});
+{{- end }}
+{{ template "__ga_js_set_doNotTrack" . }}
+if (!doNotTrack) {
+	var gaScript = document.createElement('script');
+	gaScript.async = true;
+	gaScript.src = 'https://www.googletagmanager.com/gtag/js?id={{ .Site.GoogleAnalytics }}';
+	document.head.appendChild(gaScript);
+	gtag('js', new Date());
+	var gaConfig = {};
+	{{- if $pc.AnonymizeIP }}
+	gaConfig['anonymize_ip'] = true;
+	{{- end }}
+	{{- if $pc.UseSessionStorage }}
+	if (window.sessionStorage) {
+		var GA_SESSION_STORAGE_KEY = 'ga:clientId';
+		var clientId = sessionStorage.getItem(GA_SESSION_STORAGE_KEY);
+		if (!clientId) {
+			clientId = Math.floor(Math.random() * 2147483647) + '.' + Math.floor(Date.now() / 1000);
+			sessionStorage.setItem(GA_SESSION_STORAGE_KEY, clientId);
+		}
+		gaConfig['client_storage'] = 'none';
+		gaConfig['client_id'] = clientId;
+	}
+	{{- end }}
+	gtag('config', '{{ .Site.GoogleAnalytics }}', gaConfig);
+}
+</script>
+{{- end -}}
+`},
 	{`google_analytics_async.html`, `{{- $pc := .Site.Config.Privacy.GoogleAnalytics -}}
 {{- if not $pc.Disable -}}
 {{ with .Site.GoogleAnalytics }}
+{{- if hasPrefix . "G-" }}
+{{ template "__ga4" $ }}
+{{- else }}
 <script type="application/javascript">
 {{ template "__ga_js_set_doNotTrack" $ }}
 if (!doNotTrack) {
@@ -173,34 +618,111 @@ if (!doNotTrack) {
 }
 </script>
 <script async src='https://www.google-analytics.com/analytics.js'></script>
+{{- end }}
 {{ end }}
 {{- end -}}
 `},
 	{`google_news.html`, `{{ if .IsPage }}{{ with .Params.news_keywords }}
   <meta name="news_keywords" content="{{ range $i, $kw := first 10 . }}{{ if $i }},{{ end }}{{ $kw }}{{ end }}" />
 {{ end }}{{ end }}`},
-	{`opengraph.html`, `<meta property="og:title" content="{{ .Title }}" />
-<meta property="og:description" content="{{ with .Description }}{{ . }}{{ else }}{{if .IsPage}}{{ .Summary }}{{ else }}{{ with .Site.Params.description }}{{ . }}{{ end }}{{ end }}{{ end }}" />
-<meta property="og:type" content="{{ if .IsPage }}article{{ else }}website{{ end }}" />
-<meta property="og:url" content="{{ .Permalink }}" />
-{{ with $.Param "images" }}{{ range first 6 . }}
-<meta property="og:image" content="{{ . | absURL }}" />
-{{ end }}{{ end }}
+	{`opengraph.html`, `{{- $title := .Title | default .Site.Title }}
+{{- $titleLength := 95 }}{{ with .Site.Params.opengraph }}{{ if isset . "titlelength" }}{{ $titleLength = int (index . "titlelength") }}{{ end }}{{ end -}}
+<meta property="og:title" content="{{ if gt $titleLength 0 }}{{ truncate $titleLength $title }}{{ else }}{{ $title }}{{ end }}" />
+{{- $termDescription := "" }}
+{{- /* Term pages without a description get the number of pages with the term. Hugo's English opengraphTermDescription translation is the default. */}}
+{{- if and (eq .Kind "taxonomy") (not .Description) }}
+{{- $termDescription = i18n "opengraphTermDescription" (dict "Count" (index .Data .Data.Singular).Count "Term" .Title "Taxonomy" .Data.Singular) }}
+{{- end }}
+{{- $descriptionLength := 200 }}{{ with .Site.Params.opengraph }}{{ if isset . "descriptionlength" }}{{ $descriptionLength = int (index . "descriptionlength") }}{{ end }}{{ end }}
+{{- $description := newScratch }}{{ template "__social_description" (dict "page" . "description" $termDescription "length" $descriptionLength "scratch" $description) }}
+{{- with $description.Get "description" }}
+<meta property="og:description" content="{{ . }}" />
+{{- end }}
+{{- $ogType := cond .IsPage "article" "website" }}
+{{- if .IsPage }}{{ with .Site.Params.opengraph }}{{ with index . "types" }}{{ with index . $.Section }}{{ $ogType = . }}{{ end }}{{ end }}{{ end }}{{ end }}
+{{- with .Params.ogType }}{{ $ogType = . }}{{ end }}
+<meta property="og:type" content="{{ $ogType }}" />
+{{- /* Type specific properties, e.g. profile:first_name, from the front matter map named after the type's namespace. */}}
+{{- $namespace := index (split $ogType ".") 0 }}
+{{- if not (in (slice "article" "website") $namespace) }}{{ with index .Params $namespace }}{{ if reflect.IsMap . }}
+{{- range $key, $value := . }}{{ range cond (reflect.IsSlice $value) $value (slice $value) }}
+<meta property="{{ $namespace }}:{{ $key }}" content="{{ . }}" />
+{{- end }}{{ end }}{{ end }}{{ end }}{{ end }}
+{{- $canonical := newScratch }}{{ template "__canonical_url" (dict "page" . "url" .Permalink "scratch" $canonical) }}
+<meta property="og:url" content="{{ $canonical.Get "url" }}" />
+{{- $imageAspect := "" }}{{ $maxImages := 6 }}{{ $imageBaseURL := "" }}
+{{- with .Site.Params.opengraph }}
+{{- with index . "imageaspect" }}{{ $imageAspect = . }}{{ end }}
+{{- if isset . "maximages" }}{{ $maxImages = int (index . "maximages") }}{{ end }}
+{{- with index . "imagebaseurl" }}
+{{- if not (findRE "^(https?:)?//" .) }}{{ errorf "params.opengraph.imageBaseURL must be an absolute URL, got %q" . }}{{ end }}
+{{- $imageBaseURL = printf "%s/" (strings.TrimSuffix "/" .) }}
+{{- end }}
+{{- end }}
+{{- $siteBaseURL := printf "%s/" (strings.TrimSuffix "/" (string .Site.BaseURL)) }}
+{{- /* Image precedence: ogImage (optionally per output format), images, a featured resource and the site images. */}}
+{{- $images := slice }}
+{{- with .Params.ogImage }}
+{{- $ogImage := . }}
+{{- if reflect.IsMap . }}{{ $ogImage = "" }}
+{{- range $.OutputFormats }}{{ if not ($.AlternativeOutputFormats.Get .Name) }}{{ with index $.Params.ogImage (lower .Name) }}{{ $ogImage = . }}{{ end }}{{ end }}{{ end }}
+{{- end }}
+{{- with $ogImage }}{{ $images = cond (reflect.IsSlice .) . (slice .) }}{{ end }}
+{{- end }}
+{{- if not $images }}{{ with .Params.images }}{{ $images = . }}{{ end }}{{ end }}
+{{- $ogImages := slice }}
+{{- range $images }}{{ $ogImages = $ogImages | append (dict "path" . "url" (. | absURL)) }}{{ end }}
+{{- if not $ogImages }}
+{{- $featured := newScratch }}{{ template "__featured_image" (dict "page" . "scratch" $featured) }}
+{{- with $featured.Get "image" }}{{ $ogImages = slice (dict "path" .Name "url" .Permalink) }}{{ end }}
+{{- end }}
+{{- if not $ogImages }}
+{{- range .Site.Params.images }}{{ $ogImages = $ogImages | append (dict "path" . "url" (. | absURL)) }}{{ end }}
+{{- end }}
+{{ range first $maxImages $ogImages }}
+{{- $path := .path }}{{ $url := .url }}
+{{- $processed := newScratch }}{{ template "__social_image" (dict "page" $ "path" $path "aspect" $imageAspect "scratch" $processed) }}
+{{- $image := $processed.Get "image" }}
+{{- with $image }}{{ $url = .Permalink }}{{ else }}{{ with $.Resources.GetMatch $path }}{{ if and (eq .ResourceType "image") (ne .MediaType.SubType "svg") }}{{ $image = . }}{{ end }}{{ end }}{{ end }}
+{{- with $imageBaseURL }}{{ if hasPrefix $url $siteBaseURL }}{{ $url = printf "%s%s" . (strings.TrimPrefix $siteBaseURL $url) }}{{ end }}{{ end }}
+<meta property="og:image" content="{{ $url }}" />
+{{- with $image }}
+<meta property="og:image:width" content="{{ .Width }}" />
+<meta property="og:image:height" content="{{ .Height }}" />
+{{- end }}
+{{ template "__og_media_type" (dict "page" $ "path" $path "property" "og:image:type") }}
+{{ end }}
 
 {{- $iso8601 := "2006-01-02T15:04:05-07:00" -}}
-{{- if .IsPage }}
+{{- if eq $ogType "article" }}
 {{- if not .PublishDate.IsZero }}<meta property="article:published_time" {{ .PublishDate.Format $iso8601 | printf "content=%q" | safeHTMLAttr }} />
 {{ else if not .Date.IsZero }}<meta property="article:published_time" {{ .Date.Format $iso8601 | printf "content=%q" | safeHTMLAttr }} />
 {{ end }}
 {{- if not .Lastmod.IsZero }}<meta property="article:modified_time" {{ .Lastmod.Format $iso8601 | printf "content=%q" | safeHTMLAttr }} />{{ end }}
+{{- $expirationTime := true }}{{ with .Site.Params.opengraph }}{{ if isset . "expirationtime" }}{{ $expirationTime = index . "expirationtime" }}{{ end }}{{ end }}
+{{- if and $expirationTime (not .ExpiryDate.IsZero) }}
+<meta property="article:expiration_time" {{ .ExpiryDate.Format $iso8601 | printf "content=%q" | safeHTMLAttr }} />{{ end }}
 {{- else }}
 {{- if not .Date.IsZero }}
 <meta property="og:updated_time" {{ .Date.Format $iso8601 | printf "content=%q" | safeHTMLAttr }} />
 {{- end }}
-{{- end }}{{/* .IsPage */}}
+{{- end }}{{/* article */}}
 
 {{- with .Params.audio }}<meta property="og:audio" content="{{ . }}" />{{ end }}
-{{- with .Params.locale }}<meta property="og:locale" content="{{ . }}" />{{ end }}
+{{- /* The locale defaults to the language code, or the language, in the Open Graph format, e.g. en-us to en_US. The locales map in params.opengraph overrides it per language code or language. */}}
+{{- $locale := .Params.locale }}
+{{- if not $locale }}
+{{- $code := lower (.Site.LanguageCode | default .Site.Language.Lang) }}
+{{- $parts := split (replace $code "_" "-") "-" }}
+{{- $locale = index $parts 0 }}{{ if gt (len $parts) 1 }}{{ $locale = printf "%s_%s" $locale (upper (index $parts (sub (len $parts) 1))) }}{{ end }}
+{{- with .Site.Params.opengraph }}{{ with index . "locales" }}{{ with index . $code | default (index . (lower $.Site.Language.Lang)) }}{{ $locale = . }}{{ end }}{{ end }}{{ end }}
+{{- end }}
+{{- /* A locale without a region isn't valid Open Graph, so use the language's main locale, or leave the tag out. */}}
+{{- if and $locale (not (in $locale "_")) }}
+{{- $defaults := dict "ar" "ar_AR" "cs" "cs_CZ" "da" "da_DK" "de" "de_DE" "el" "el_GR" "en" "en_US" "es" "es_ES" "fi" "fi_FI" "fr" "fr_FR" "he" "he_IL" "hi" "hi_IN" "hu" "hu_HU" "id" "id_ID" "it" "it_IT" "ja" "ja_JP" "ko" "ko_KR" "nb" "nb_NO" "nl" "nl_NL" "nn" "nn_NO" "pl" "pl_PL" "pt" "pt_PT" "ro" "ro_RO" "ru" "ru_RU" "sv" "sv_SE" "th" "th_TH" "tr" "tr_TR" "uk" "uk_UA" "vi" "vi_VN" "zh" "zh_CN" }}
+{{- $locale = index $defaults (lower $locale) | default "" }}
+{{- end }}
+{{- with $locale }}<meta property="og:locale" content="{{ . }}" />{{ end }}
 {{- with .Site.Params.title }}<meta property="og:site_name" content="{{ . }}" />{{ end }}
 {{- with .Params.videos }}
 {{- range . }}
@@ -209,25 +731,53 @@ if (!doNotTrack) {
 
 {{- /* If it is part of a series, link to related articles */}}
 {{- $permalink := .Permalink }}
-{{- $siteSeries := .Site.Taxonomies.series }}{{ with .Params.series }}
-{{- range $name := . }}
+{{- $seriesTaxonomy := "series" }}{{ $seriesLimit := 6 }}
+{{- with .Site.Params.opengraph }}
+{{- with index . "seriestaxonomy" }}{{ $seriesTaxonomy = . }}{{ end }}
+{{- if isset . "serieslimit" }}{{ $seriesLimit = int (index . "serieslimit") }}{{ end }}
+{{- end }}
+{{- with index .Site.Taxonomies $seriesTaxonomy }}{{ $siteSeries := . }}{{ with index $.Params $seriesTaxonomy }}
+{{- range $name := cond (reflect.IsSlice .) . (slice .) }}
   {{- $series := index $siteSeries $name }}
-  {{- range $page := first 6 $series.Pages }}
-    {{- if ne $page.Permalink $permalink }}<meta property="og:see_also" content="{{ $page.Permalink }}" />{{ end }}
+  {{- range $page := first $seriesLimit $series.Pages }}
+    {{- if ne $page.Permalink $permalink }}{{ template "__canonical_url" (dict "page" $ "url" $page.Permalink "scratch" $canonical) }}<meta property="og:see_also" content="{{ $canonical.Get "url" }}" />{{ end }}
   {{- end }}
-{{ end }}{{ end }}
+{{ end }}{{ end }}{{ end }}
 
-{{- if .IsPage }}
-{{- range .Site.Authors }}{{ with .Social.facebook }}
-<meta property="article:author" content="https://www.facebook.com/{{ . }}" />{{ end }}{{ with .Site.Social.facebook }}
+{{- if eq $ogType "article" }}
+{{- with .Params.authors }}{{ range cond (reflect.IsSlice .) . (slice .) }}{{ with index $.Site.Authors . }}{{ with .Social.facebook }}
+<meta property="article:author" content="{{ if findRE "^https?://" . }}{{ . }}{{ else }}https://www.facebook.com/{{ . }}{{ end }}" />{{ end }}{{ end }}{{ end }}{{ end }}{{ with .Site.Social.facebook }}
 <meta property="article:publisher" content="https://www.facebook.com/{{ . }}" />{{ end }}
-<meta property="article:section" content="{{ .Section }}" />
-{{- with .Params.tags }}{{ range first 6 . }}
-<meta property="article:tag" content="{{ . }}" />{{ end }}{{ end }}
-{{- end }}{{ end }}
+{{- /* The page's section first, then any extra sections from the front matter. */}}
+{{- $sections := slice }}{{ with .Section }}{{ $sections = $sections | append . }}{{ end }}
+{{- with .Params.sections }}{{ range cond (reflect.IsSlice .) . (slice .) }}{{ if not (in $sections .) }}{{ $sections = $sections | append . }}{{ end }}{{ end }}{{ end }}
+{{- range $sections }}
+<meta property="article:section" content="{{ . }}" />{{ end }}
+{{- $keywords := newScratch }}{{ template "__schema_keywords" (dict "page" . "scratch" $keywords) }}
+{{- with $keywords.Get "tags" }}{{ range first 6 . }}
+<meta property="article:tag" content="{{ . }}" />{{ end }}
+{{- else }}{{ if $keywords.Get "emitEmpty" }}
+<meta property="article:tag" content="" />{{ end }}{{ end }}
+{{- end }}
 
 {{- /* Facebook Page Admin ID for Domain Insights */}}
 {{- with .Site.Social.facebook_admin }}<meta property="fb:admins" content="{{ . }}" />{{ end }}
+{{- /* Facebook App ID for Insights */}}
+{{- with .Site.Config.Services.Facebook.AppID }}
+<meta property="fb:app_id" content="{{ . }}" />{{ end }}
+
+{{- define "__og_media_type" -}}{{/* Resolves the MIME type from a page resource or the file extension. */}}
+{{- $type := "" -}}
+{{- with .page.Resources.GetMatch .path -}}
+{{- $type = .MediaType.Type -}}
+{{- /* Hugo's JPEG media type is image/jpg, but image/jpeg is the registered one. */ -}}
+{{- if eq $type "image/jpg" }}{{ $type = "image/jpeg" }}{{ end -}}
+{{- else -}}
+{{- $types := dict "jpg" "image/jpeg" "jpeg" "image/jpeg" "png" "image/png" "webp" "image/webp" "gif" "image/gif" "mp4" "video/mp4" "webm" "video/webm" "ogv" "video/ogg" -}}
+{{- $type = index $types (path.Ext .path | lower | strings.TrimPrefix ".") -}}
+{{- end -}}
+{{- with $type }}<meta property="{{ $.property }}" content="{{ . }}" />{{ end -}}
+{{- end -}}
 `},
 	{`pagination.html`, `{{ $pag := $.Paginator }}
 {{ if gt $pag.TotalPages 1 }}
@@ -270,20 +820,228 @@ if (!doNotTrack) {
 </ul>
 {{ end }}
 `},
-	{`schema.html`, `<meta itemprop="name" content="{{ .Title }}">
-<meta itemprop="description" content="{{ with .Description }}{{ . }}{{ else }}{{if .IsPage}}{{ .Summary }}{{ else }}{{ with .Site.Params.description }}{{ . }}{{ end }}{{ end }}{{ end }}">
+	{`schema.html`, `{{- with .Title | default .Site.Title }}<meta itemprop="name" content="{{ . }}">{{ end }}
+{{- $description := "" }}{{ with .Description }}{{ $description = . }}{{ else }}{{ if .IsPage }}{{ $description = .Summary }}{{ else }}{{ with .Site.Params.description }}{{ $description = . }}{{ end }}{{ end }}{{ end }}
+{{- with $description }}
+<meta itemprop="description" content="{{ . }}">
+{{- end }}
 
 {{if .IsPage}}{{ $ISO8601 := "2006-01-02T15:04:05-07:00" }}{{ if not .PublishDate.IsZero }}
 <meta itemprop="datePublished" content="{{ .PublishDate.Format $ISO8601 | safeHTML }}" />{{ end }}
 {{ if not .Lastmod.IsZero }}<meta itemprop="dateModified" content="{{ .Lastmod.Format $ISO8601 | safeHTML }}" />{{ end }}
 <meta itemprop="wordCount" content="{{ .WordCount }}">
-{{ with .Params.images }}{{ range first 6 . }}
-  <meta itemprop="image" content="{{ . | absURL }}">
+{{- $scratch := newScratch }}{{ template "__schema_type" (dict "page" . "scratch" $scratch) }}
+{{- if eq ($scratch.Get "type") "NewsArticle" }}{{ with .Params.schema }}
+{{- with index . "dateline" }}
+<meta itemprop="dateline" content="{{ . }}">{{ end }}
+{{- with index . "printsection" }}
+<meta itemprop="printSection" content="{{ . }}">{{ end }}
+{{- end }}{{ end }}
+{{ with .Params.images }}{{ range first 6 . }}{{ $url := . | absURL }}
+{{- $processed := newScratch }}{{ template "__social_image" (dict "page" $ "path" . "scratch" $processed) }}
+{{- with $processed.Get "image" }}{{ $url = .Permalink }}{{ end }}
+  <meta itemprop="image" content="{{ $url }}">
 {{ end }}{{ end }}
-
-<!-- Output all taxonomies as schema.org keywords -->
-<meta itemprop="keywords" content="{{ if .IsPage}}{{ range $index, $tag := .Params.tags }}{{ $tag }},{{ end }}{{ else }}{{ range $plural, $terms := .Site.Taxonomies }}{{ range $term, $val := $terms }}{{ printf "%s," $term }}{{ end }}{{ end }}{{ end }}" />
-{{ end }}`},
+{{ end }}
+<!-- Output the tags, or all taxonomy terms on list pages, as schema.org keywords -->
+{{- $keywords := newScratch }}{{ template "__schema_keywords" (dict "page" . "scratch" $keywords) }}
+{{- with $keywords.Get "keywords" }}
+<meta itemprop="keywords" content="{{ . }}" />
+{{- else }}{{ if $keywords.Get "emitEmpty" }}
+<meta itemprop="keywords" content="" />{{ end }}{{ end }}`},
+	{`schema_article.html`, `{{- if .IsPage -}}
+{{- $scratch := newScratch }}{{ template "__schema_type" (dict "page" . "scratch" $scratch) -}}
+{{- $type := $scratch.Get "type" -}}
+{{- $path := .RelPermalink }}{{ with .File }}{{ $path = .Path }}{{ end -}}
+{{- if not (in (slice "Article" "NewsArticle" "BlogPosting") $type) -}}
+{{- warnf "The schema type of %q must be one of Article, NewsArticle or BlogPosting, got %q; using Article" $path $type -}}
+{{- $type = "Article" -}}
+{{- end -}}
+{{- $iso8601 := "2006-01-02T15:04:05-07:00" -}}
+{{- if gt (strings.RuneCount .Title) 110 }}{{ warnf "The %s headline of %q is longer than the 110 characters Google allows" $type $path }}{{ end -}}
+{{- $canonical := newScratch }}{{ template "__canonical_url" (dict "page" . "url" .Permalink "scratch" $canonical) -}}
+{{- $schema := dict "@context" "https://schema.org" "@type" $type "headline" .Title "mainEntityOfPage" ($canonical.Get "url") "wordCount" .WordCount -}}
+{{- with .Description | default .Summary | plainify | htmlUnescape }}{{ $schema = merge $schema (dict "description" (trim . " \n")) }}{{ end -}}
+{{- $published := cond .PublishDate.IsZero .Date .PublishDate -}}
+{{- if not $published.IsZero }}{{ $schema = merge $schema (dict "datePublished" ($published.Format $iso8601)) }}{{ end -}}
+{{- if not .Lastmod.IsZero }}{{ $schema = merge $schema (dict "dateModified" (.Lastmod.Format $iso8601)) }}{{ end -}}
+{{- $images := slice -}}
+{{- $imageObjects := true }}{{ with .Site.Params.schema }}{{ if isset . "imagedimensions" }}{{ $imageObjects = index . "imagedimensions" }}{{ end }}{{ end -}}
+{{- range .Params.images -}}
+{{- $image := . | absURL -}}
+{{- /* Image page resources, processed or not, are emitted as an ImageObject with their dimensions. Remote URLs are kept as is. */ -}}
+{{- if and $imageObjects (not (findRE "^(https?:)?//" .)) -}}
+{{- $processed := newScratch }}{{ template "__social_image" (dict "page" $ "path" . "scratch" $processed) -}}
+{{- $resource := $processed.Get "image" -}}
+{{- if not $resource }}{{ with $.Resources.GetMatch . }}{{ if and (eq .ResourceType "image") (ne .MediaType.SubType "svg") }}{{ $resource = . }}{{ end }}{{ end }}{{ end -}}
+{{- with $resource }}{{ $image = dict "@type" "ImageObject" "url" .Permalink "width" .Width "height" .Height }}{{ end -}}
+{{- end -}}
+{{- $images = $images | append $image -}}
+{{- end -}}
+{{- with $images }}{{ $schema = merge $schema (dict "image" .) }}{{ end -}}
+{{- /* The authors front matter, falling back to author and the site author, correlated with the .Site.Authors profiles. An author without a profile is emitted with the name as given, an author map with its name. A single author is emitted as an object, several as an array. */ -}}
+{{- $authors := slice -}}
+{{- $networks := dict "facebook" "https://www.facebook.com/" "github" "https://github.com/" "instagram" "https://www.instagram.com/" "linkedin" "https://www.linkedin.com/in/" "pinterest" "https://www.pinterest.com/" "twitter" "https://twitter.com/" "youtube" "https://www.youtube.com/" -}}
+{{- with .Params.authors | default .Params.author | default .Site.Author.name -}}
+{{- range cond (reflect.IsSlice .) . (slice .) -}}
+{{- /* An author map, e.g. from [[author]] tables, gives the name; other authors are keys into .Site.Authors. */ -}}
+{{- $name := "" }}{{ $profile := false -}}
+{{- if reflect.IsMap . }}{{ with index . "name" }}{{ $name = string . }}{{ end }}{{ else }}{{ $name = string . }}{{ with index $.Site.Authors $name }}{{ $profile = . }}{{ end }}{{ end -}}
+{{- with $name -}}
+{{- $person := dict "@type" "Person" "name" . -}}
+{{- with $profile -}}
+{{- with .DisplayName | default (trim (printf "%s %s" .GivenName .FamilyName) " ") }}{{ $person = merge $person (dict "name" .) }}{{ end -}}
+{{- $sameAs := slice -}}
+{{- range $network, $account := .Social -}}
+{{- $url := $account -}}
+{{- if not (findRE "^https?://" $account) -}}
+{{- $url = "" -}}
+{{- if eq $network "website" }}{{ $url = printf "https://%s" $account }}{{ else }}{{ with index $networks $network }}{{ $url = printf "%s%s" . (strings.TrimPrefix "@" $account) }}{{ end }}{{ end -}}
+{{- end -}}
+{{- with $url -}}
+{{- if eq $network "website" }}{{ $person = merge $person (dict "url" .) }}{{ else }}{{ $sameAs = $sameAs | append . }}{{ end -}}
+{{- end -}}
+{{- end -}}
+{{- with $sameAs }}{{ $person = merge $person (dict "sameAs" .) }}{{ end -}}
+{{- end -}}
+{{- $authors = $authors | append $person -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- if eq (len $authors) 1 }}{{ $schema = merge $schema (dict "author" (index $authors 0)) }}{{ else if $authors }}{{ $schema = merge $schema (dict "author" $authors) }}{{ end -}}
+{{- with .Site.Title }}{{ $schema = merge $schema (dict "publisher" (dict "@type" "Organization" "name" .)) }}{{ end -}}
+{{- with .Section }}{{ $schema = merge $schema (dict "articleSection" .) }}{{ end -}}
+{{- $keywords := newScratch }}{{ template "__schema_keywords" (dict "page" . "scratch" $keywords) -}}
+{{- with $keywords.Get "keywords" }}{{ $schema = merge $schema (dict "keywords" .) }}{{ end -}}
+{{- if eq $type "NewsArticle" }}{{ with .Params.schema -}}
+{{- with index . "dateline" }}{{ $schema = merge $schema (dict "dateline" .) }}{{ end -}}
+{{- with index . "printsection" }}{{ $schema = merge $schema (dict "printSection" .) }}{{ end -}}
+{{- end }}{{ end -}}
+<script type="application/ld+json">{{ $schema | jsonify | safeJS }}</script>
+{{ end -}}
+`},
+	{`schema_breadcrumbs.html`, `{{- $breadcrumbs := newScratch }}{{ template "__breadcrumbs" (dict "page" . "scratch" $breadcrumbs) -}}
+{{- $trail := $breadcrumbs.Get "trail" -}}
+{{- if gt (len $trail) 1 -}}
+{{- $items := slice }}{{ $canonical := newScratch -}}
+{{- range $i, $p := $trail -}}
+{{- template "__canonical_url" (dict "page" $ "url" $p.Permalink "scratch" $canonical) -}}
+{{- $items = $items | append (dict "@type" "ListItem" "position" (add $i 1) "name" $p.Title "item" ($canonical.Get "url")) -}}
+{{- end -}}
+<script type="application/ld+json">{{ dict "@context" "https://schema.org" "@type" "BreadcrumbList" "itemListElement" $items | jsonify | safeJS }}</script>
+{{ end -}}
+`},
+	{`schema_collection.html`, `{{- /* The pager is passed in, never created here: .Paginator would fix the paginator of the page to .Pages before the list template calls .Paginate. */ -}}
+{{- if reflect.IsMap . -}}
+{{- $page := .page -}}
+{{- with .paginator -}}
+{{- if .Pages -}}
+{{- $offset := mul (sub .PageNumber 1) .PageSize -}}
+{{- $parts := slice }}{{ $canonical := newScratch -}}
+{{- range $i, $p := .Pages -}}
+{{- template "__canonical_url" (dict "page" $page "url" $p.Permalink "scratch" $canonical) -}}
+{{- $parts = $parts | append (dict "@type" "CreativeWork" "position" (add $offset (add $i 1)) "name" $p.Title "url" ($canonical.Get "url")) -}}
+{{- end -}}
+{{- $name := $page.Title -}}
+{{- if gt .TotalPages 1 -}}
+{{- $name = printf "%s - Page %d of %d" $page.Title .PageNumber .TotalPages -}}
+{{- end -}}
+{{- template "__canonical_url" (dict "page" $page "url" (.URL | absURL) "scratch" $canonical) -}}
+{{- $schema := dict "@context" "https://schema.org" "@type" "CollectionPage" "name" $name "url" ($canonical.Get "url") "position" .PageNumber "numberOfItems" .TotalNumberOfElements "hasPart" $parts -}}
+{{- if gt .PageNumber 1 -}}
+{{- template "__canonical_url" (dict "page" $page "url" (.First.URL | absURL) "scratch" $canonical) -}}
+{{- $schema = merge $schema (dict "isPartOf" (dict "@type" "CollectionPage" "url" ($canonical.Get "url"))) -}}
+{{- end -}}
+<script type="application/ld+json">{{ $schema | jsonify | safeJS }}</script>
+{{ end -}}
+{{- end -}}
+{{- else -}}
+{{- $path := .RelPermalink }}{{ with .File }}{{ $path = .Path }}{{ end -}}
+{{- warnf "The schema_collection.html template in %q must be called with a dict with the page and its paginator" $path -}}
+{{- end -}}
+`},
+	{`schema_search.html`, `{{- if .IsHome -}}
+{{- with .Site.Params.search -}}
+{{- with .url -}}
+{{- if not (in . "{search_term_string}") -}}
+{{- errorf "params.search.url must contain the {search_term_string} placeholder, got %q" . -}}
+{{- else -}}
+{{- $target := . -}}
+{{- if not (findRE "^(https?:)?//" $target) -}}
+{{- $target = printf "%s/%s" (strings.TrimSuffix "/" (string $.Site.BaseURL)) (strings.TrimPrefix "/" $target) -}}
+{{- end -}}
+{{- $action := dict "@type" "SearchAction" "target" $target "query-input" "required name=search_term_string" -}}
+{{- $canonical := newScratch }}{{ template "__canonical_url" (dict "page" $ "url" $.Permalink "scratch" $canonical) -}}
+{{- $schema := dict "@context" "https://schema.org" "@type" "WebSite" "name" $.Site.Title "url" ($canonical.Get "url") "potentialAction" $action -}}
+<script type="application/ld+json">{{ $schema | jsonify | safeJS }}</script>
+{{ end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`shortcodes/__h_googlemap.html`, `{{- define "__h_googlemap" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves the location, zoom and embed URL of the googlemap shortcodes. Expects a dict with the shortcode and a scratch to store the values in. */ -}}
+{{- $sc := .shortcode -}}
+{{- $location := $sc.Get "query" | default ($sc.Get 0) -}}
+{{- with $sc.Get "lat" }}{{ with $sc.Get "lon" }}{{ $location = printf "%s,%s" ($sc.Get "lat") . }}{{ end }}{{ end -}}
+{{- if not $location -}}
+{{- errorf "The %q shortcode requires coordinates or a query: %s" $sc.Name $sc.Position -}}
+{{- end -}}
+{{- $zoom := int ($sc.Get "zoom" | default 14) -}}
+{{- $q := $location | urlquery -}}
+{{- $embed := printf "https://maps.google.com/maps?q=%s&z=%d&output=embed" $q $zoom -}}
+{{- with $sc.Page.Site.Config.Services.GoogleMaps.APIKey -}}
+{{- $embed = printf "https://www.google.com/maps/embed/v1/place?key=%s&q=%s&zoom=%d" (urlquery .) $q $zoom -}}
+{{- end -}}
+{{- .scratch.Set "location" $location -}}
+{{- .scratch.Set "zoom" $zoom -}}
+{{- .scratch.Set "embed" $embed -}}
+{{- end -}}
+`},
+	{`shortcodes/__h_oembed_fallback.html`, `{{- define "__h_oembed_fallback" -}}{{/* These template definitions are global. */}}
+{{- /* Renders the fallback of the embed shortcodes when the remote resource could not be fetched. Expects a dict with the shortcode, the link to the resource and the link text. Fails the build instead if services.oembed.failOnError is set. */ -}}
+{{- if .shortcode.Page.Site.Config.Services.OEmbed.FailOnError -}}
+{{- errorf "The %q shortcode failed to fetch %s: %s" .shortcode.Name .link .shortcode.Position -}}
+{{- else -}}
+{{- warnf "The %q shortcode failed to fetch %s, rendering a link instead: %s" .shortcode.Name .link .shortcode.Position -}}
+<a href="{{ .link }}">{{ .text }}</a>
+{{- end -}}
+{{- end -}}
+`},
+	{`shortcodes/__h_picture.html`, `{{- define "__h_picture_image" -}}{{/* These template definitions are global. */}}
+{{- /* Resolves an image of the picture shortcodes. Expects a dict with the shortcode, the src, an optional process spec, e.g. "fill 600x800 Center", and a scratch to store the url and, for image page resources, the width and height in. */ -}}
+{{- $sc := .shortcode -}}
+{{- $url := .src }}{{ $width := 0 }}{{ $height := 0 -}}
+{{- with $sc.Page.Resources.GetMatch .src -}}
+{{- $image := . -}}
+{{- with $.process -}}
+{{- $fields := split . " " -}}
+{{- $method := lower (index $fields 0) -}}
+{{- $spec := string (delimit (after 1 $fields) " ") -}}
+{{- if eq $method "fill" }}{{ $image = $image.Fill $spec -}}
+{{- else if eq $method "fit" }}{{ $image = $image.Fit $spec -}}
+{{- else if eq $method "resize" }}{{ $image = $image.Resize $spec -}}
+{{- else }}{{ errorf "The %q shortcode process must start with fill, fit or resize, got %q: %s" $sc.Name . $sc.Position }}{{ end -}}
+{{- end -}}
+{{- $url = $image.RelPermalink -}}
+{{- if and (eq $image.ResourceType "image") (ne $image.MediaType.SubType "svg") }}{{ $width = $image.Width }}{{ $height = $image.Height }}{{ end -}}
+{{- else -}}
+{{- if .process }}{{ errorf "The %q shortcode can only process page resources, got %q: %s" $sc.Name .src $sc.Position }}{{ end -}}
+{{- end -}}
+{{- .scratch.Set "url" $url -}}
+{{- .scratch.Set "width" $width -}}
+{{- .scratch.Set "height" $height -}}
+{{- end -}}
+`},
+	{`shortcodes/__h_playground.html`, `{{- define "__h_playground" -}}{{/* These template definitions are global. */}}
+{{- /* Renders the lazy loaded iframe of the code playground shortcodes. Expects a dict with the shortcode, the embed URL as src, a title and the default height. The shortcode's height, in pixels or any CSS length, title and class parameters take precedence. */ -}}
+{{- $height := .shortcode.Get "height" | default .height -}}
+{{- if findRE "^[0-9]+$" $height }}{{ $height = printf "%spx" $height }}{{ end -}}
+<div class="playground playground-{{ .shortcode.Name }}{{ with .shortcode.Get "class" }} {{ . }}{{ end }}">
+  <iframe src="{{ .src }}" style="width: 100%; height: {{ $height }}; border: 0;" loading="lazy" title="{{ .shortcode.Get "title" | default .title }}" allowfullscreen></iframe>
+</div>
+{{- end -}}
+`},
 	{`shortcodes/__h_simple_assets.html`, `{{ define "__h_simple_css" }}{{/* These template definitions are global. */}}
 {{- if not (.Page.Scratch.Get "__h_simple_css") -}}
 {{/* Only include once */}}
@@ -319,16 +1077,205 @@ if (!doNotTrack) {
 <svg version="1" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 61 61"><circle cx="30.5" cy="30.5" r="30.5" opacity=".8" fill="#000"></circle><path d="M25.3 19.2c-2.1-1.2-3.8-.2-3.8 2.2v18.1c0 2.4 1.7 3.4 3.8 2.2l16.6-9.1c2.1-1.2 2.1-3.2 0-4.4l-16.6-9z" fill="#fff"></path></svg>
 {{- end -}}
 `},
-	{`shortcodes/figure.html`, `<figure{{ with .Get "class" }} class="{{ . }}"{{ end }}>
+	{`shortcodes/__h_table_sort.html`, `{{ define "__h_table_sort" }}{{/* These template definitions are global. */}}
+{{- /* Sorts the rows of the sortable tables when a header cell is clicked, numerically if both values are numbers. Only included once per page. */ -}}
+{{- if not (.Page.Scratch.Get "__h_table_sort") -}}
+{{- .Page.Scratch.Set "__h_table_sort" true }}
+<script>
+document.addEventListener("DOMContentLoaded", function () {
+  Array.prototype.forEach.call(document.querySelectorAll("table.sortable th"), function (th) {
+    th.style.cursor = "pointer";
+    th.addEventListener("click", function () {
+      var headers = th.parentNode.children, i = Array.prototype.indexOf.call(headers, th), tbody = th.closest("table").tBodies[0];
+      var asc = th.getAttribute("aria-sort") !== "ascending";
+      Array.prototype.forEach.call(headers, function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+      Array.prototype.slice.call(tbody.rows).sort(function (a, b) {
+        var x = a.cells[i].textContent, y = b.cells[i].textContent, n = parseFloat(x) - parseFloat(y);
+        var c = isNaN(n) ? x.localeCompare(y) : n;
+        return asc ? c : -c;
+      }).forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+});
+</script>
+{{- end -}}
+{{ end }}
+`},
+	{`shortcodes/asciinema.html`, `{{- $id := .Get "id" -}}
+{{- $src := .Get "src" -}}
+{{- with .Get 0 }}{{ if strings.HasSuffix . ".cast" }}{{ $src = . }}{{ else }}{{ $id = . }}{{ end }}{{ end -}}
+{{- $theme := .Get "theme" -}}
+{{- $speed := .Get "speed" -}}
+{{- $autoplay := eq (.Get "autoplay") "true" -}}
+{{- with $src -}}
+{{- with $.Page.Resources.GetMatch . -}}
+{{- $src = .RelPermalink -}}
+{{- else -}}
+{{- if not (or (hasPrefix $src "/") (in $src "://")) -}}
+{{- errorf "The %q shortcode could not find the resource %q: %s" $.Name $src $.Position -}}
+{{- end -}}
+{{- end -}}
+<asciinema-player src="{{ $src }}"
+  {{- with $theme }} theme="{{ . }}"{{ end -}}
+  {{- with $speed }} speed="{{ . }}"{{ end -}}
+  {{- if $autoplay }} autoplay{{ end -}}
+></asciinema-player>
+{{- else -}}
+{{- with $id -}}
+{{- if eq ($.Get "loading") "lazy" -}}
+{{- $query := slice -}}
+{{- with $theme }}{{ $query = $query | append (printf "theme=%s" (. | urlquery)) }}{{ end -}}
+{{- with $speed }}{{ $query = $query | append (printf "speed=%s" (. | urlquery)) }}{{ end -}}
+{{- if $autoplay }}{{ $query = $query | append "autoplay=1" }}{{ end -}}
+<iframe src="https://asciinema.org/a/{{ . }}/iframe{{ with $query }}?{{ delimit . "&" | safeURL }}{{ end }}" loading="lazy" style="width: 100%; border: 0;" title="asciicast {{ . }}" allowfullscreen></iframe>
+{{- else -}}
+<script id="asciicast-{{ . }}" src="https://asciinema.org/a/{{ . }}.js"
+  {{- with $theme }} data-theme="{{ . }}"{{ end -}}
+  {{- with $speed }} data-speed="{{ . }}"{{ end -}}
+  {{- if $autoplay }} data-autoplay="true"{{ end }} async></script>
+{{- end -}}
+{{- else -}}
+{{- errorf "The %q shortcode requires a recording id or a .cast file: %s" $.Name $.Position -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`shortcodes/attachments.html`, `{{- $include := .Get "include" | default (.Get 0) | default "*" -}}
+{{- $exclude := .Get "exclude" -}}
+{{- $sort := lower (.Get "sort" | default "name") -}}
+{{- $order := lower (.Get "order" | default "asc") -}}
+{{- if not (in (slice "name" "size") $sort) -}}
+{{- errorf "The %q shortcode sort must be name or size, got %q: %s" .Name $sort .Position -}}
+{{- end -}}
+{{- if not (in (slice "asc" "desc") $order) -}}
+{{- errorf "The %q shortcode order must be asc or desc, got %q: %s" .Name $order .Position -}}
+{{- end -}}
+{{- $excluded := slice -}}
+{{- with $exclude }}{{ range split . "," }}{{ range $.Page.Resources.Match (trim . " ") }}{{ $excluded = $excluded | append .Name }}{{ end }}{{ end }}{{ end -}}
+{{- $seen := slice }}{{ $attachments := slice -}}
+{{- range split $include "," -}}
+{{- range $.Page.Resources.Match (trim . " ") -}}
+{{- if and (ne .ResourceType "page") (not (in $seen .Name)) (not (in $excluded .Name)) -}}
+{{- $seen = $seen | append .Name -}}
+{{- $attachments = $attachments | append (dict "name" (path.Base .Name) "title" (cond (eq .Title .Name) (path.Base .Name) .Title) "size" .Size "url" .RelPermalink) -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- with $attachments }}
+<ul class="attachments">
+{{- range sort . $sort $order }}
+{{- $size := printf "%d B" .size -}}
+{{- if ge .size 1048576 }}{{ $size = printf "%.1f MB" (div (float .size) 1048576) }}{{ else if ge .size 1024 }}{{ $size = printf "%.1f KB" (div (float .size) 1024) }}{{ end }}
+  <li><a href="{{ .url }}" download="{{ .name }}">{{ .title }}</a> <span class="attachment-size">{{ $size }}</span></li>
+{{- end }}
+</ul>
+{{- end -}}
+`},
+	{`shortcodes/audio.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src: %s" .Name .Position -}}
+{{- end -}}
+{{- $types := dict "mp3" "audio/mpeg" "m4a" "audio/mp4" "aac" "audio/aac" "ogg" "audio/ogg" "oga" "audio/ogg" "opus" "audio/opus" "wav" "audio/wav" "flac" "audio/flac" -}}
+{{- $sources := slice -}}
+{{- if or (hasPrefix $src "/") (in $src "://") -}}
+{{- $sources = slice (dict "src" $src "type" (index $types (path.Ext $src | lower | strings.TrimPrefix "."))) -}}
+{{- else -}}
+{{- with .Page.Resources.GetMatch $src -}}
+{{- $name := .Name -}}
+{{- $ext := path.Ext .Name -}}
+{{- $base := strings.TrimSuffix $ext .Name -}}
+{{- /* Add the other formats of the same recording found in the bundle, e.g. an ogg for an mp3. */ -}}
+{{- range $.Page.Resources.Match (printf "%s.*" $base) -}}
+{{- $type := cond (eq .MediaType.MainType "audio") .MediaType.Type (index $types (path.Ext .Name | lower | strings.TrimPrefix ".")) -}}
+{{- if or $type (eq .Name $name) -}}
+{{- $source := dict "src" .RelPermalink "type" $type -}}
+{{- $sources = cond (eq .Name $name) (slice $source | append $sources) ($sources | append $source) -}}
+{{- end -}}
+{{- end -}}
+{{- else -}}
+{{- errorf "The %q shortcode could not find the resource %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- end -}}
+{{- $title := .Get "title" -}}
+{{- $caption := .Get "caption" -}}
+{{- if or $title $caption }}
+<figure class="audio">
+{{- end }}
+<audio controls preload="{{ .Get "preload" | default "metadata" }}"{{ with .Get "class" }} class="{{ . }}"{{ end }}{{ with $title }} title="{{ . }}"{{ end }}>
+{{- range $sources }}
+  <source src="{{ .src }}"{{ with .type }} type="{{ . }}"{{ end }}>
+{{- end }}
+</audio>
+{{- if or $title $caption }}
+  <figcaption>
+    {{- with $title }}<strong>{{ . }}</strong>{{ end -}}
+    {{- with $caption }}{{ if $title }} {{ end }}{{ . | markdownify }}{{ end -}}
+  </figcaption>
+</figure>
+{{- end }}
+`},
+	{`shortcodes/codesandbox.html`, `{{- $id := .Get "id" | default (.Get 0) | replaceRE "^(https?:)?//codesandbox\\.io/(s|embed)/|[?#].*$|/$" "" -}}
+{{- if not $id -}}
+{{- errorf "The %q shortcode requires a sandbox id or URL: %s" .Name .Position -}}
+{{- else if not (findRE "^[A-Za-z0-9_-]+$" $id) -}}
+{{- errorf "The %q shortcode got an invalid sandbox id %q: %s" .Name $id .Position -}}
+{{- end -}}
+{{- $query := slice -}}
+{{- with .Get "view" -}}
+{{- if not (in (slice "editor" "split" "preview") .) }}{{ errorf "The %q shortcode view must be editor, split or preview, got %q: %s" $.Name . $.Position }}{{ end -}}
+{{- $query = $query | append (printf "view=%s" .) -}}
+{{- end -}}
+{{- with .Get "file" }}{{ $query = $query | append (printf "module=%s" (urlquery .)) }}{{ end -}}
+{{- with .Get "theme" }}{{ $query = $query | append (printf "theme=%s" (urlquery .)) }}{{ end -}}
+{{- $src := printf "https://codesandbox.io/embed/%s" $id -}}
+{{- with $query }}{{ $src = printf "%s?%s" $src (delimit . "&") }}{{ end -}}
+{{- template "__h_playground" (dict "shortcode" . "src" $src "title" (printf "CodeSandbox %s" $id) "height" "500") -}}
+`},
+	{`shortcodes/faq.html`, `{{- /* The question shortcodes inside this block register themselves in .Scratch. */ -}}
+<div class="faq">
+{{ .Inner }}
+</div>
+{{- with .Scratch.Get "entries" }}
+{{- $mainEntity := slice -}}
+{{- range . -}}
+{{- $mainEntity = $mainEntity | append (dict "@type" "Question" "name" .question "acceptedAnswer" (dict "@type" "Answer" "text" .answer)) -}}
+{{- end }}
+<script type="application/ld+json">{{ dict "@context" "https://schema.org" "@type" "FAQPage" "mainEntity" $mainEntity | jsonify | safeJS }}</script>
+{{- end -}}
+`},
+	{`shortcodes/figure.html`, `{{- $captionFormat := .Get "captionformat" | default "markdown" -}}
+{{- if not (in (slice "markdown" "inline" "plain") $captionFormat) -}}
+{{- errorf "The %q shortcode has an unknown captionformat %q, must be one of markdown, inline or plain: %s" .Name $captionFormat .Position -}}
+{{- end -}}
+{{- $caption := .Get "caption" -}}
+{{- $captionAlt := $caption -}}
+{{- if ne $captionFormat "plain" -}}
+{{- $caption = $caption | markdownify -}}
+{{- $captionAlt = $caption | plainify -}}
+{{- if eq $captionFormat "inline" -}}
+{{- $caption = $caption | replaceRE "</p>\\s*<p>" " " | replaceRE "^\\s*<p>|</p>\\s*$" "" | safeHTML -}}
+{{- end -}}
+{{- end -}}
+{{- $scratch := newScratch }}{{ template "__image_loading" (dict "shortcode" . "scratch" $scratch) -}}
+{{- $image := false -}}
+{{- with .Get "src" }}{{ with $.Page.Resources.GetMatch . }}{{ if and (eq .ResourceType "image") (ne .MediaType.SubType "svg") -}}
+{{- $image = . -}}
+{{- template "__image_srcset" (dict "page" $.Page "image" . "scratch" $scratch) -}}
+{{- end }}{{ end }}{{ end -}}
+{{- template "__image_placeholder" (dict "shortcode" . "image" $image "scratch" $scratch) -}}
+<figure{{ with .Get "class" }} class="{{ . }}"{{ end }}>
     {{- if .Get "link" -}}
         <a href="{{ .Get "link" }}"{{ with .Get "target" }} target="{{ . }}"{{ end }}{{ with .Get "rel" }} rel="{{ . }}"{{ end }}>
     {{- end }}
     <img src="{{ .Get "src" }}"
          {{- if or (.Get "alt") (.Get "caption") }}
-         alt="{{ with .Get "alt" }}{{ . }}{{ else }}{{ .Get "caption" | markdownify| plainify }}{{ end }}"
+         alt="{{ with .Get "alt" }}{{ . }}{{ else }}{{ $captionAlt }}{{ end }}"
          {{- end -}}
          {{- with .Get "width" }} width="{{ . }}"{{ end -}}
          {{- with .Get "height" }} height="{{ . }}"{{ end -}}
+         {{- with $scratch.Get "srcset" }} srcset="{{ . }}"{{ with $.Get "sizes" }} sizes="{{ . }}"{{ end }}{{ end -}}
+         {{- with $scratch.Get "loading" }} loading="{{ . }}"{{ end -}}
+         {{- with $scratch.Get "placeholder" }} style="{{ safeCSS . }}" onload="this.style.backgroundImage='none'"{{ end -}}
     /> <!-- Closing img tag -->
     {{- if .Get "link" }}</a>{{ end -}}
     {{- if or (or (.Get "title") (.Get "caption")) (.Get "attr") -}}
@@ -337,7 +1284,7 @@ if (!doNotTrack) {
                 <h4>{{ . }}</h4>
             {{- end -}}
             {{- if or (.Get "caption") (.Get "attr") -}}<p>
-                {{- .Get "caption" | markdownify -}}
+                {{- $caption -}}
                 {{- with .Get "attrlink" }}
                     <a href="{{ . }}">
                 {{- end -}}
@@ -348,7 +1295,82 @@ if (!doNotTrack) {
     {{- end }}
 </figure>
 `},
-	{`shortcodes/gist.html`, `<script type="application/javascript" src="https://gist.github.com/{{ index .Params 0 }}/{{ index .Params 1 }}.js{{if len .Params | eq 3 }}?file={{ index .Params 2 }}{{end}}"></script>
+	{`shortcodes/gist.html`, `{{- $scheme := cond (eq (.Get "protocolRelative") "true") "//" "https://" -}}
+{{- $user := .Get "user" | default (.Get 0) -}}
+{{- $id := .Get "id" | default (.Get 1) -}}
+{{- $file := .Get "file" | default (.Get 2) -}}
+<script type="application/javascript" src="{{ $scheme }}gist.github.com/{{ $user }}/{{ $id }}.js{{ with $file }}?file={{ . }}{{ end }}"></script>
+`},
+	{`shortcodes/github.html`, `{{- $repo := .Get "repo" | default (.Get 0) -}}
+{{- if not (findRE "^[^/\\s]+/[^/\\s]+$" $repo) -}}
+{{- errorf "The %q shortcode requires a repository as owner/repo: %s" .Name .Position -}}
+{{- else -}}
+{{- $url := printf "https://api.github.com/repos/%s" $repo -}}
+{{- $item := getOEmbed $url -}}
+{{- $name := "" }}{{ with $item }}{{ with .full_name }}{{ $name = . }}{{ end }}{{ end -}}
+{{- with $name -}}
+<div class="github-card">
+  <a class="github-card-name" href="{{ $item.html_url }}">{{ . }}</a>
+  {{- with $item.description }}
+  <p class="github-card-description">{{ . }}</p>
+  {{- end }}
+  <p class="github-card-meta">
+    {{- with $item.language }}<span class="github-card-language">{{ . }}</span> {{ end -}}
+    <span class="github-card-stars" title="Stars">&#9733; {{ $item.stargazers_count }}</span>
+  </p>
+</div>
+{{- else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://github.com/%s" $repo) "text" (printf "%s on GitHub" $repo)) -}}
+{{- end -}}
+{{- end -}}
+`},
+	{`shortcodes/googlemap.html`, `{{- $pc := .Page.Site.Config.Privacy.GoogleMaps -}}
+{{- if not $pc.Disable -}}
+{{- if $pc.Simple -}}
+{{ template "_internal/shortcodes/googlemap_simple.html" . }}
+{{- else -}}
+{{- $map := newScratch }}{{ template "__h_googlemap" (dict "shortcode" . "scratch" $map) -}}
+{{- $class := .Get "class" }}
+<div {{ with $class }}class="{{ . }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
+  <iframe src="{{ $map.Get "embed" }}" {{ if not $class }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}loading="lazy" referrerpolicy="no-referrer-when-downgrade" allowfullscreen title="Google Map"></iframe>
+</div>
+{{ end -}}
+{{- end -}}
+`},
+	{`shortcodes/googlemap_simple.html`, `{{- $key := .Page.Site.Config.Services.GoogleMaps.APIKey -}}
+{{- if not $key -}}
+{{- errorf "The %q shortcode requires services.googleMaps.apiKey for the Static Maps API: %s" .Name .Position -}}
+{{- end -}}
+{{- $map := newScratch }}{{ template "__h_googlemap" (dict "shortcode" . "scratch" $map) -}}
+{{- $q := $map.Get "location" | urlquery -}}
+{{- $static := printf "https://maps.googleapis.com/maps/api/staticmap?center=%s&zoom=%d&size=640x360&markers=%s&key=%s" $q ($map.Get "zoom") $q (urlquery $key) -}}
+{{ $class := .Get "class" }}
+{{ $hasClass := $class }}
+{{ $class := $class | default "__h_video" }}
+{{ if not $hasClass }}
+{{/* If class is set, assume the user wants to provide his own styles. */}}
+{{ template "__h_simple_css" $ }}
+{{ end }}
+{{ $scratch := newScratch }}{{ template "__image_loading" (dict "shortcode" . "scratch" $scratch) }}
+<div class="s_map_simple {{ $class }}">
+<a href="https://www.google.com/maps/search/?api=1&amp;query={{ $map.Get "location" }}" data-h-map="{{ $map.Get "embed" }}" target="_blank">
+<img src="{{ $static }}" alt="Map of {{ $map.Get "location" }}" loading="{{ $scratch.Get "loading" }}">
+<div class="play">{{ template "__h_simple_icon_play" $ }}</div></a></div>
+{{- if not (.Page.Scratch.Get "__h_googlemap_js") }}{{ .Page.Scratch.Set "__h_googlemap_js" true }}
+<script>
+document.addEventListener("click", function (e) {
+  var a = e.target.closest && e.target.closest("a[data-h-map]");
+  if (!a) return;
+  e.preventDefault();
+  var f = document.createElement("iframe");
+  f.src = a.getAttribute("data-h-map");
+  f.title = "Google Map";
+  f.allowFullscreen = true;
+  f.style.cssText = "position: absolute; top: 0; left: 0; width: 100%; height: 100%; border: 0;";
+  a.parentNode.replaceChild(f, a);
+});
+</script>
+{{- end }}
 `},
 	{`shortcodes/highlight.html`, `{{ if len .Params | eq 2 }}{{ highlight (trim .Inner "\n\r") (.Get 0) (.Get 1) }}{{ else }}{{ highlight (trim .Inner "\n\r") (.Get 0) "" }}{{ end }}`},
 	{`shortcodes/instagram.html`, `{{- $pc := .Page.Site.Config.Privacy.Instagram -}}
@@ -358,17 +1380,20 @@ if (!doNotTrack) {
 {{- else -}}
 {{ $id := .Get 0 }}
 {{ $hideCaption := cond (eq (.Get 1) "hidecaption") "1" "0" }}
-{{ with getJSON "https://api.instagram.com/oembed/?url=https://instagram.com/p/" $id "/&hidecaption=" $hideCaption  }}{{ .html | safeHTML }}{{ end }}
+{{ with getOEmbed "https://api.instagram.com/oembed/?url=https://instagram.com/p/" $id "/&hidecaption=" $hideCaption  }}{{ .html | safeHTML }}{{ else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://www.instagram.com/p/%s/" $id) "text" "View this post on Instagram") -}}
+{{- end }}
 {{- end -}}
 {{- end -}}`},
 	{`shortcodes/instagram_simple.html`, `{{- $pc := .Page.Site.Config.Privacy.Instagram -}}
 {{- $sc := .Page.Site.Config.Services.Instagram -}}
 {{- if not $pc.Disable -}}
 {{- $id := .Get 0 -}}
-{{- $item := getJSON "https://api.instagram.com/oembed/?url=https://www.instagram.com/p/" $id "/&amp;maxwidth=640&amp;omitscript=true" -}}
+{{- $item := getOEmbed "https://api.instagram.com/oembed/?url=https://www.instagram.com/p/" $id "/&amp;maxwidth=640&amp;omitscript=true" -}}
 {{- $class1 := "__h_instagram" -}}
 {{- $class2 := "s_instagram_simple" -}}
 {{- $hideCaption := (eq (.Get 1) "hidecaption") -}}
+{{- $scratch := newScratch }}{{ template "__image_loading" (dict "shortcode" . "scratch" $scratch) -}}
 {{ with $item }}
 {{- $mediaURL := printf "https://instagram.com/p/%s/" $id | safeURL -}}
 {{- if not $sc.DisableInlineCSS -}}
@@ -378,13 +1403,15 @@ if (!doNotTrack) {
 	<div class="card-header">
     <a href="{{ $item.author_url | safeURL }}" class="card-link">{{ $item.author_name }}</a>
   </div>
-	<a href="{{ $mediaURL }}" target="_blank"><img class="card-img-top img-fluid" src="{{ $item.thumbnail_url }}" width="{{ $item.thumbnail_width }}"  height="{{ $item.thumbnail_height }}" alt="Instagram Image"></a>
+	<a href="{{ $mediaURL }}" target="_blank"><img class="card-img-top img-fluid" src="{{ $item.thumbnail_url }}" width="{{ $item.thumbnail_width }}"  height="{{ $item.thumbnail_height }}" alt="Instagram Image" loading="{{ $scratch.Get "loading" }}"></a>
 	<div class="card-body">
 		{{ if not $hideCaption }}<p class="card-text"><a href="{{ $item.author_url | safeURL }}" class="card-link">{{ $item.author_name }}</a> {{ $item.title}}</p>{{ end }}
 		<a href="{{ $item.author_url | safeURL }}" class="card-link">View More on Instagram</a>
 	</div>
 </div>
-{{ end }}
+{{ else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://www.instagram.com/p/%s/" $id) "text" "View this post on Instagram") -}}
+{{- end }}
 {{- end -}}
 
 {{ define "__h_simple_instagram_css" }}
@@ -409,31 +1436,233 @@ if (!doNotTrack) {
 </style>
 {{ end }}
 {{ end }}`},
+	{`shortcodes/jsfiddle.html`, `{{- $id := .Get "id" | default (.Get 0) | replaceRE "^(https?:)?//jsfiddle\\.net/|/embedded/.*$|/$" "" -}}
+{{- if not $id -}}
+{{- errorf "The %q shortcode requires a fiddle id or URL: %s" .Name .Position -}}
+{{- else if not (findRE "^[A-Za-z0-9_-]+(/[A-Za-z0-9_-]+)*$" $id) -}}
+{{- errorf "The %q shortcode got an invalid fiddle id %q: %s" .Name $id .Position -}}
+{{- end -}}
+{{- $src := printf "https://jsfiddle.net/%s/embedded/%s/" $id (.Get "tabs" | default "js,html,css,result") -}}
+{{- with .Get "theme" }}{{ $src = printf "%s%s/" $src . }}{{ end -}}
+{{- template "__h_playground" (dict "shortcode" . "src" $src "title" (printf "JSFiddle %s" $id) "height" "300") -}}
+`},
 	{`shortcodes/param.html`, `{{- $name := (.Get 0) -}}
 {{- with $name -}}
 {{- with ($.Page.Param .) }}{{ . }}{{ else }}{{ errorf "Param %q not found: %s" $name $.Position }}{{ end -}}
 {{- else }}{{ errorf "Missing param key: %s" $.Position }}{{ end -}}`},
+	{`shortcodes/pdf.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src: %s" .Name .Position -}}
+{{- end -}}
+{{- $title := .Get "title" -}}
+{{- if or (hasPrefix $src "/") (in $src "://") -}}
+{{- $title = $title | default (path.Base $src) -}}
+{{- else -}}
+{{- with .Page.Resources.GetMatch $src -}}
+{{- $src = .RelPermalink -}}
+{{- $title = $title | default .Title -}}
+{{- else -}}
+{{- errorf "The %q shortcode could not find the resource %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- end -}}
+{{- $height := .Get "height" | default "600px" -}}
+{{- if findRE "^[0-9]+$" $height }}{{ $height = printf "%spx" $height }}{{ end -}}
+{{- $viewer := $src -}}
+{{- with .Get "page" }}{{ $viewer = printf "%s#page=%d" $src (int .) }}{{ end -}}
+<div class="pdf{{ with .Get "class" }} {{ . }}{{ end }}">
+  <iframe src="{{ $viewer }}" style="width: 100%; height: {{ $height }}; border: 0;" loading="lazy" title="{{ $title }}"></iframe>
+  <p><a href="{{ $src }}" download>Download {{ $title }}</a></p>
+</div>
+`},
+	{`shortcodes/picture.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src for the fallback image: %s" .Name .Position -}}
+{{- end -}}
+{{- /* The source shortcodes inside this block register themselves in .Scratch. The inner content itself isn't rendered. */ -}}
+{{- $inner := .Inner -}}
+{{- $image := newScratch }}{{ template "__h_picture_image" (dict "shortcode" . "src" $src "process" (.Get "process") "scratch" $image) -}}
+{{- $scratch := newScratch }}{{ template "__image_loading" (dict "shortcode" . "scratch" $scratch) -}}
+<picture{{ with .Get "class" }} class="{{ . }}"{{ end }}>
+{{- range .Scratch.Get "sources" }}
+  <source{{ with .media }} media="{{ . }}"{{ end }}{{ with .type }} type="{{ . }}"{{ end }} srcset="{{ .srcset }}"{{ with .width }} width="{{ . }}"{{ end }}{{ with .height }} height="{{ . }}"{{ end }}>
+{{- end }}
+  <img src="{{ $image.Get "url" }}" alt="{{ .Get "alt" }}"
+    {{- with $image.Get "width" }} width="{{ . }}"{{ end }}
+    {{- with $image.Get "height" }} height="{{ . }}"{{ end }} loading="{{ $scratch.Get "loading" }}">
+</picture>
+`},
+	{`shortcodes/question.html`, `{{- $question := .Get "question" | default (.Get 0) -}}
+{{- if not $question -}}
+{{- errorf "The %q shortcode requires a question: %s" .Name .Position -}}
+{{- end -}}
+{{- $answer := .Inner | markdownify -}}
+{{- with .Parent -}}
+{{- if eq .Name "faq" -}}
+{{- .Scratch.Add "entries" (slice (dict "question" $question "answer" (string $answer))) -}}
+{{- end -}}
+{{- end }}
+<details class="faq-question">
+  <summary>{{ $question }}</summary>
+  <div class="faq-answer">{{ $answer }}</div>
+</details>
+`},
+	{`shortcodes/quote.html`, `{{- $author := .Get "author" -}}
+{{- $source := .Get "source" -}}
+{{- $url := .Get "url" -}}
+{{- $schema := eq (.Get "schema") "true" -}}
+{{- $class := "quote" }}{{ with .Get "class" }}{{ $class = printf "%s %s" $class . }}{{ end }}
+<blockquote class="{{ $class }}"{{ with $url }} cite="{{ . }}"{{ end }}{{ if $schema }} itemscope itemtype="https://schema.org/Quotation"{{ end }}>
+  <div class="quote-text"{{ if $schema }} itemprop="text"{{ end }}>{{ .Inner | markdownify }}</div>
+  {{- if or $author $source }}
+  <footer>
+    {{- with $author }}<span class="quote-author"{{ if $schema }} itemprop="creator" itemscope itemtype="https://schema.org/Person"><span itemprop="name">{{ . }}</span>{{ else }}>{{ . }}{{ end }}</span>{{ end -}}
+    {{- if and $author $source }}, {{ end -}}
+    {{- with $source }}<cite>{{ with $url }}<a href="{{ . }}"{{ if $schema }} itemprop="isBasedOn"{{ end }}>{{ $source }}</a>{{ else }}{{ . }}{{ end }}</cite>{{ end -}}
+  </footer>
+  {{- end }}
+</blockquote>
+`},
 	{`shortcodes/ref.html`, `{{ ref . .Params }}`},
 	{`shortcodes/relref.html`, `{{ relref . .Params }}`},
+	{`shortcodes/source.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src: %s" .Name .Position -}}
+{{- end -}}
+{{- $parent := "" }}{{ with .Parent }}{{ $parent = .Name }}{{ end -}}
+{{- if ne $parent "picture" -}}
+{{- errorf "The %q shortcode must be used inside a picture shortcode: %s" .Name .Position -}}
+{{- end -}}
+{{- /* The picture shortcode emits the sources in the order they are registered. */ -}}
+{{- $image := newScratch }}{{ template "__h_picture_image" (dict "shortcode" . "src" $src "process" (.Get "process") "scratch" $image) -}}
+{{- .Parent.Scratch.Add "sources" (slice (dict "srcset" ($image.Get "url") "media" (.Get "media") "type" (.Get "type") "width" ($image.Get "width") "height" ($image.Get "height"))) -}}
+`},
+	{`shortcodes/stackblitz.html`, `{{- $id := .Get "id" | default (.Get 0) | replaceRE "^(https?:)?//stackblitz\\.com/edit/|[?#].*$|/$" "" -}}
+{{- if not $id -}}
+{{- errorf "The %q shortcode requires a project id or URL: %s" .Name .Position -}}
+{{- else if not (findRE "^[A-Za-z0-9_-]+$" $id) -}}
+{{- errorf "The %q shortcode got an invalid project id %q: %s" .Name $id .Position -}}
+{{- end -}}
+{{- $query := slice "embed=1" -}}
+{{- with .Get "view" -}}
+{{- if not (in (slice "editor" "both" "preview") .) }}{{ errorf "The %q shortcode view must be editor, both or preview, got %q: %s" $.Name . $.Position }}{{ end -}}
+{{- $query = $query | append (printf "view=%s" .) -}}
+{{- end -}}
+{{- with .Get "file" }}{{ $query = $query | append (printf "file=%s" (urlquery .)) }}{{ end -}}
+{{- with .Get "theme" }}{{ $query = $query | append (printf "theme=%s" (urlquery .)) }}{{ end -}}
+{{- $src := printf "https://stackblitz.com/edit/%s?%s" $id (delimit $query "&") -}}
+{{- template "__h_playground" (dict "shortcode" . "src" $src "title" (printf "StackBlitz %s" $id) "height" "500") -}}
+`},
+	{`shortcodes/table.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src: %s" .Name .Position -}}
+{{- end -}}
+{{- $delimiter := .Get "delimiter" | default "," -}}
+{{- $data := "" -}}
+{{- if in $src "://" -}}
+{{- $format := lower (.Get "format") | default (strings.TrimPrefix "." (lower (path.Ext (index (split $src "?") 0)))) -}}
+{{- if eq $format "csv" }}{{ $data = getCSV $delimiter $src -}}
+{{- else if eq $format "json" }}{{ $data = getJSON $src -}}
+{{- else }}{{ errorf "The %q shortcode can only read CSV or JSON, got %q, set the format for URLs without an extension: %s" .Name $src .Position }}{{ end -}}
+{{- else -}}
+{{- with .Page.Resources.GetMatch $src -}}
+{{- $format := .MediaType.SubType -}}
+{{- if eq $format "csv" }}{{ $data = transform.Unmarshal (dict "delimiter" $delimiter) . -}}
+{{- else if eq $format "json" }}{{ $data = transform.Unmarshal . -}}
+{{- else }}{{ errorf "The %q shortcode can only read CSV or JSON, got %q: %s" $.Name $src $.Position }}{{ end -}}
+{{- else -}}
+{{- errorf "The %q shortcode could not find the resource %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- end -}}
+{{- if or (not (reflect.IsSlice $data)) (not $data) -}}
+{{- errorf "The %q shortcode found no rows in %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- /* A list of rows has its header in the first row, a list of objects uses the keys of the first object. */ -}}
+{{- $header := slice }}{{ $rows := slice -}}
+{{- $first := index $data 0 -}}
+{{- if reflect.IsMap $first -}}
+{{- range $key, $_ := $first }}{{ $header = $header | append $key }}{{ end -}}
+{{- range $data -}}
+{{- if not (reflect.IsMap .) }}{{ errorf "The %q shortcode expects %q to be a list of objects or rows: %s" $.Name $src $.Position }}{{ end -}}
+{{- end -}}
+{{- $rows = $data -}}
+{{- else if reflect.IsSlice $first -}}
+{{- range $first }}{{ $header = $header | append (string .) }}{{ end -}}
+{{- $rows = after 1 $data -}}
+{{- range $rows -}}
+{{- if not (reflect.IsSlice .) }}{{ errorf "The %q shortcode expects %q to be a list of objects or rows: %s" $.Name $src $.Position }}{{ end -}}
+{{- end -}}
+{{- else -}}
+{{- errorf "The %q shortcode expects %q to be a list of objects or rows: %s" .Name $src .Position -}}
+{{- end -}}
+{{- if not $header -}}
+{{- errorf "The %q shortcode found no columns in %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- /* The indexes of the columns to show, all by default. */ -}}
+{{- $columns := seq 0 (sub (len $header) 1) -}}
+{{- with .Get "columns" -}}
+{{- $columns = slice -}}
+{{- range split . "," }}{{ with trim . " " -}}
+{{- $column := . }}{{ $found := false -}}
+{{- range $i, $name := $header }}{{ if and (not $found) (eq $name $column) }}{{ $columns = $columns | append $i }}{{ $found = true }}{{ end }}{{ end -}}
+{{- if not $found }}{{ errorf "The %q shortcode got the unknown column %q for %q: %s" $.Name $column $src $.Position }}{{ end -}}
+{{- end }}{{ end -}}
+{{- end -}}
+{{- $sortable := eq (.Get "sortable") "true" }}
+<table class="table{{ with .Get "class" }} {{ . }}{{ end }}{{ if $sortable }} sortable{{ end }}">
+  {{- with .Get "caption" }}
+  <caption>{{ . }}</caption>
+  {{- end }}
+  <thead>
+    <tr>
+      {{- range $columns }}
+      <th>{{ index $header . }}</th>
+      {{- end }}
+    </tr>
+  </thead>
+  <tbody>
+    {{- range $rows }}{{ $cells := . }}
+    <tr>
+      {{- range $columns }}
+      <td>{{ if reflect.IsMap $cells }}{{ index $cells (index $header .) }}{{ else if lt . (len $cells) }}{{ index $cells . }}{{ end }}</td>
+      {{- end }}
+    </tr>
+    {{- end }}
+  </tbody>
+</table>
+{{- if $sortable }}{{ template "__h_table_sort" . }}{{ end -}}
+`},
 	{`shortcodes/twitter.html`, `{{- $pc := .Page.Site.Config.Privacy.Twitter -}}
 {{- if not $pc.Disable -}}
 {{- if $pc.Simple -}}
 {{ template "_internal/shortcodes/twitter_simple.html" . }}
 {{- else -}}
-{{- $url := printf "https://api.twitter.com/1/statuses/oembed.json?id=%s&dnt=%t" (index .Params 0) $pc.EnableDNT -}}
-{{- $json := getJSON $url -}}
-{{ $json.html | safeHTML }}
+{{- $scratch := newScratch }}{{ template "__tweet_id" (dict "shortcode" . "scratch" $scratch) -}}
+{{- $id := $scratch.Get "id" -}}
+{{- $url := printf "https://api.twitter.com/1/statuses/oembed.json?id=%s&dnt=%t" $id $pc.EnableDNT -}}
+{{- $json := getOEmbed $url -}}
+{{- $html := "" }}{{ with $json }}{{ with .html }}{{ $html = . }}{{ end }}{{ end -}}
+{{- with $html -}}
+{{ . | safeHTML }}
+{{- else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://twitter.com/i/status/%s" $id) "text" "View this tweet on Twitter") -}}
+{{- end }}
 {{- end -}}
 {{- end -}}`},
 	{`shortcodes/twitter_simple.html`, `{{- $pc := .Page.Site.Config.Privacy.Twitter -}}
 {{- $sc := .Page.Site.Config.Services.Twitter -}}
 {{- if not $pc.Disable -}}
-{{- $id := .Get 0 -}}
-{{- $json := getJSON "https://api.twitter.com/1/statuses/oembed.json?id=" $id "&omit_script=true" -}}
+{{- $scratch := newScratch }}{{ template "__tweet_id" (dict "shortcode" . "scratch" $scratch) -}}
+{{- $id := $scratch.Get "id" -}}
+{{- $json := getOEmbed "https://api.twitter.com/1/statuses/oembed.json?id=" $id "&omit_script=true" -}}
+{{- $html := "" }}{{ with $json }}{{ with .html }}{{ $html = . }}{{ end }}{{ end -}}
+{{- with $html -}}
 {{- if not $sc.DisableInlineCSS -}}
 {{ template "__h_simple_twitter_css" $ }}
 {{- end -}}
-{{ $json.html | safeHTML }}
+{{ . | safeHTML }}
+{{- else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://twitter.com/i/status/%s" $id) "text" "View this tweet on Twitter") -}}
+{{- end }}
 {{- end -}}
 
 {{ define "__h_simple_twitter_css" }}
@@ -458,22 +1687,100 @@ if (!doNotTrack) {
 </style>
 {{ end }}
 {{ end }}`},
+	{`shortcodes/twitter_timeline.html`, `{{- $pc := .Page.Site.Config.Privacy.Twitter -}}
+{{- if not $pc.Disable -}}
+{{- if $pc.Simple -}}
+{{- errorf "The %q shortcode requires Twitter's widgets.js and does not support privacy.twitter.simple: %s" .Name .Position -}}
+{{- end -}}
+{{- $user := .Get "user" -}}
+{{- $collection := .Get "collection" -}}
+{{- with .Get 0 }}{{ if in . "://" }}{{ $collection = . }}{{ else }}{{ $user = . }}{{ end }}{{ end -}}
+{{- $href := "" }}{{ $text := "" -}}
+{{- with $collection -}}
+{{- $href = . }}{{ $text = "A Twitter collection" -}}
+{{- else -}}
+{{- with $user -}}
+{{- $user = strings.TrimPrefix "@" . -}}
+{{- $href = printf "https://twitter.com/%s" $user }}{{ $text = printf "Tweets by %s" $user -}}
+{{- else -}}
+{{- errorf "The %q shortcode requires a user or a collection: %s" .Name .Position -}}
+{{- end -}}
+{{- end -}}
+{{- $theme := .Get "theme" -}}
+{{- with $theme }}{{ if not (in (slice "light" "dark") .) }}{{ errorf "The %q shortcode theme must be light or dark, got %q: %s" $.Name . $.Position }}{{ end }}{{ end -}}
+<a class="twitter-timeline" href="{{ $href }}"
+{{- with .Get "height" }} data-height="{{ . }}"{{ end }}
+{{- with $theme }} data-theme="{{ . }}"{{ end }}
+{{- with .Get "limit" }} data-tweet-limit="{{ . }}"{{ end }}
+{{- if $pc.EnableDNT }} data-dnt="true"{{ end }}>{{ $text }}</a>
+{{- if not (.Page.Scratch.Get "__h_twitter_widgets") }}{{ .Page.Scratch.Set "__h_twitter_widgets" true }}
+<script async src="https://platform.twitter.com/widgets.js" charset="utf-8"></script>
+{{- end }}
+{{- end -}}
+`},
+	{`shortcodes/video.html`, `{{- $src := .Get "src" | default (.Get 0) -}}
+{{- if not $src -}}
+{{- errorf "The %q shortcode requires a src: %s" .Name .Position -}}
+{{- end -}}
+{{- $types := dict "mp4" "video/mp4" "webm" "video/webm" "ogv" "video/ogg" -}}
+{{- $sources := slice -}}
+{{- if or (hasPrefix $src "/") (in $src "://") -}}
+{{- $sources = slice (dict "src" $src "type" (index $types (path.Ext $src | lower | strings.TrimPrefix "."))) -}}
+{{- else -}}
+{{- with .Page.Resources.GetMatch $src -}}
+{{- $ext := path.Ext .Name -}}
+{{- $base := strings.TrimSuffix $ext .Name -}}
+{{- $sources = slice (dict "src" .RelPermalink "type" (index $types ($ext | lower | strings.TrimPrefix "."))) -}}
+{{- /* Add the other formats of the same video found in the bundle, e.g. a webm for an mp4. */ -}}
+{{- range $alt := slice "webm" "mp4" "ogv" -}}
+{{- if ne ($ext | lower) (printf ".%s" $alt) -}}
+{{- with $.Page.Resources.GetMatch (printf "%s.%s" $base $alt) -}}
+{{- $sources = $sources | append (dict "src" .RelPermalink "type" (index $types $alt)) -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- else -}}
+{{- errorf "The %q shortcode could not find the resource %q: %s" .Name $src .Position -}}
+{{- end -}}
+{{- end -}}
+{{- $poster := "" -}}
+{{- with .Get "poster" -}}
+{{- $poster = . -}}
+{{- with $.Page.Resources.GetMatch . }}{{ $poster = .RelPermalink }}{{ end -}}
+{{- else -}}
+{{- with (.Page.Resources.ByType "image").GetMatch "*thumbnail*" }}{{ $poster = .RelPermalink }}{{ end -}}
+{{- end -}}
+{{- $preload := .Get "preload" | default (cond (eq (.Get "loading") "lazy") "none" "metadata") -}}
+{{- $autoplay := eq (.Get "autoplay") "true" -}}
+<video controls preload="{{ $preload }}"
+  {{- with $poster }} poster="{{ . }}"{{ end -}}
+  {{- with .Get "width" }} width="{{ . }}"{{ end -}}
+  {{- if $autoplay }} autoplay playsinline{{ end -}}
+  {{- if eq (.Get "muted") "true" }} muted{{ end -}}
+  {{- if eq (.Get "loop") "true" }} loop{{ end -}}
+  {{- with .Get "class" }} class="{{ . }}"{{ else }} style="max-width: 100%; height: auto;"{{ end }}>
+{{- range $sources }}
+  <source src="{{ .src }}"{{ with .type }} type="{{ . }}"{{ end }}>
+{{- end }}
+</video>
+`},
 	{`shortcodes/vimeo.html`, `{{- $pc := .Page.Site.Config.Privacy.Vimeo -}}
 {{- if not $pc.Disable -}}
 {{- if $pc.Simple -}}
 {{ template "_internal/shortcodes/vimeo_simple.html" . }}
 {{- else -}}
+{{- $scheme := cond (eq (.Get "protocolRelative") "true") "//" "https://" -}}
 {{ if .IsNamedParams }}<div {{ if .Get "class" }}class="{{ .Get "class" }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
-  <iframe src="//player.vimeo.com/video/{{ .Get "id" }}" {{ if not (.Get "class") }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}webkitallowfullscreen mozallowfullscreen allowfullscreen></iframe>
+  <iframe src="{{ $scheme }}player.vimeo.com/video/{{ .Get "id" }}" {{ if not (.Get "class") }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}webkitallowfullscreen mozallowfullscreen allowfullscreen{{ with .Get "sandbox" }} sandbox="{{ . }}"{{ end }}{{ with .Get "referrerpolicy" }} referrerpolicy="{{ . }}"{{ end }}></iframe>
  </div>{{ else }}
 <div {{ if len .Params | eq 2 }}class="{{ .Get 1 }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
-  <iframe src="//player.vimeo.com/video/{{ .Get 0 }}" {{ if len .Params | eq 1 }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}webkitallowfullscreen mozallowfullscreen allowfullscreen></iframe>
+  <iframe src="{{ $scheme }}player.vimeo.com/video/{{ .Get 0 }}" {{ if len .Params | eq 1 }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}webkitallowfullscreen mozallowfullscreen allowfullscreen></iframe>
  </div>
 {{ end }}
 {{- end -}}
 {{- end -}}`},
 	{`shortcodes/vimeo_simple.html`, `{{ $id := .Get "id" | default (.Get 0) }}
-{{- $item := getJSON "https://vimeo.com/api/oembed.json?url=https://vimeo.com/" $id -}}
+{{- $item := getOEmbed "https://vimeo.com/api/oembed.json?url=https://vimeo.com/" $id -}}
 {{ $class := .Get "class" | default (.Get 1) }}
 {{ $hasClass := $class }}
 {{ $class := $class | default "__h_video" }}
@@ -482,52 +1789,89 @@ if (!doNotTrack) {
 {{ template "__h_simple_css" $ }}
 {{ end }}
 {{ $secondClass := "s_video_simple" }}
+{{ $scratch := newScratch }}{{ template "__image_loading" (dict "shortcode" . "scratch" $scratch) }}
 <div class="{{ $secondClass }} {{ $class }}">
 {{- with $item }}
 <a href="{{ .provider_url }}{{ .video_id }}" target="_blank">
 {{ $thumb := .thumbnail_url }}
 {{ $original := $thumb | replaceRE "(_.*\\.)" "." }}
-<img src="{{ $thumb }}" srcset="{{ $thumb }} 1x, {{ $original }} 2x" alt="{{ .title }}">
+<img src="{{ $thumb }}" srcset="{{ $thumb }} 1x, {{ $original }} 2x" alt="{{ .title }}" loading="{{ $scratch.Get "loading" }}">
 <div class="play">{{ template "__h_simple_icon_play" $ }}</div></a></div>
+{{- else -}}
+{{- template "__h_oembed_fallback" (dict "shortcode" $ "link" (printf "https://vimeo.com/%s" $id) "text" "Watch this video on Vimeo") -}}
+</div>
 {{- end -}}
 `},
 	{`shortcodes/youtube.html`, `{{- $pc := .Page.Site.Config.Privacy.YouTube -}}
 {{- if not $pc.Disable -}}
 {{- $ytHost := cond $pc.PrivacyEnhanced  "www.youtube-nocookie.com" "www.youtube.com" -}}
+{{- $scheme := cond (eq (.Get "protocolRelative") "true") "//" "https://" -}}
 {{- $id := .Get "id" | default (.Get 0) -}}
 {{- $class := .Get "class" | default (.Get 1) }}
 <div {{ with $class }}class="{{ . }}"{{ else }}style="position: relative; padding-bottom: 56.25%; height: 0; overflow: hidden;"{{ end }}>
-  <iframe src="//{{ $ytHost }}/embed/{{ $id }}{{ with .Get "autoplay" }}{{ if eq . "true" }}?autoplay=1{{ end }}{{ end }}" {{ if not $class }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}allowfullscreen title="YouTube Video"></iframe>
+  <iframe src="{{ $scheme }}{{ $ytHost }}/embed/{{ $id }}{{ with .Get "autoplay" }}{{ if eq . "true" }}?autoplay=1{{ end }}{{ end }}" {{ if not $class }}style="position: absolute; top: 0; left: 0; width: 100%; height: 100%; border:0;" {{ end }}allowfullscreen {{ with .Get "sandbox" }}sandbox="{{ . }}" {{ end }}{{ with .Get "referrerpolicy" }}referrerpolicy="{{ . }}" {{ end }}title="YouTube Video"></iframe>
 </div>
 {{ end -}}
 `},
-	{`twitter_cards.html`, `{{- with $.Params.images -}}
-<meta name="twitter:card" content="summary_large_image"/>
-<meta name="twitter:image" content="{{ index . 0 | absURL }}"/>
-{{ else -}}
-{{- $images := $.Resources.ByType "image" -}}
-{{- $featured := $images.GetMatch "*feature*" -}}
-{{- $featured := cond (ne $featured nil) $featured ($images.GetMatch "{*cover*,*thumbnail*}") -}}
-{{- with $featured -}}
-<meta name="twitter:card" content="summary_large_image"/>
-<meta name="twitter:image" content="{{ $featured.Permalink }}"/>
-{{- else -}}
-{{- with $.Site.Params.images -}}
-<meta name="twitter:card" content="summary_large_image"/>
-<meta name="twitter:image" content="{{ index . 0 | absURL }}"/>
-{{ else -}}
-<meta name="twitter:card" content="summary"/>
+	{`twitter_cards.html`, `{{- $maxImages := 1 -}}
+{{- with .Site.Params.twitter -}}
+{{- if isset . "maximages" }}{{ $maxImages = int (index . "maximages") }}{{ end -}}
+{{- end -}}
+{{- /* Image precedence: images, a featured resource and the site images. */ -}}
+{{- $sources := slice -}}
+{{- range $.Params.images }}{{ $sources = $sources | append (dict "path" . "url" (. | absURL)) }}{{ end -}}
+{{- if not $sources -}}
+{{- $featured := newScratch }}{{ template "__featured_image" (dict "page" $ "scratch" $featured) -}}
+{{- with $featured.Get "image" }}{{ $sources = slice (dict "path" .Name "url" .Permalink) }}{{ end -}}
+{{- end -}}
+{{- if not $sources -}}
+{{- range $.Site.Params.images }}{{ $sources = $sources | append (dict "path" . "url" (. | absURL)) }}{{ end -}}
 {{- end -}}
+{{- $images := slice -}}
+{{- range first $maxImages $sources -}}
+{{- $url := .url -}}
+{{- $processed := newScratch }}{{ template "__social_image" (dict "page" $ "path" .path "scratch" $processed) -}}
+{{- with $processed.Get "image" }}{{ $url = .Permalink }}{{ end -}}
+{{- $images = $images | append $url -}}
 {{- end -}}
+{{- with .Site.Params.opengraph }}{{ with index . "imagebaseurl" -}}
+{{- $imageBaseURL := printf "%s/" (strings.TrimSuffix "/" .) -}}
+{{- $siteBaseURL := printf "%s/" (strings.TrimSuffix "/" (string $.Site.BaseURL)) -}}
+{{- $rebased := slice -}}
+{{- range $images }}{{ $rebased = $rebased | append (cond (hasPrefix . $siteBaseURL) (printf "%s%s" $imageBaseURL (strings.TrimPrefix $siteBaseURL .)) .) }}{{ end -}}
+{{- $images = $rebased -}}
+{{- end }}{{ end -}}
+{{- /* The card type is summary_large_image with images and summary without, unless set with twitter.card in the front matter. */ -}}
+{{- $card := cond (gt (len $images) 0) "summary_large_image" "summary" -}}
+{{- with .Params.twitter }}{{ if reflect.IsMap . }}{{ with index . "card" -}}
+{{- if in (slice "summary" "summary_large_image" "player") (lower .) }}{{ $card = lower . }}{{ else }}{{ $path := $.RelPermalink }}{{ with $.File }}{{ $path = .Path }}{{ end }}{{ warnf "Invalid twitter.card %q in %q, must be one of summary, summary_large_image or player" . $path }}{{ end -}}
+{{- end }}{{ end }}{{ end -}}
+<meta name="twitter:card" content="{{ $card }}"/>
+{{- range $images }}
+<meta name="twitter:image" content="{{ . }}"/>
 {{- end }}
-<meta name="twitter:title" content="{{ .Title }}"/>
-<meta name="twitter:description" content="{{ with .Description }}{{ . }}{{ else }}{{if .IsPage}}{{ .Summary }}{{ else }}{{ with .Site.Params.description }}{{ . }}{{ end }}{{ end }}{{ end -}}"/>
-{{ with .Site.Social.twitter -}}
-<meta name="twitter:site" content="@{{ . }}"/>
-{{ end -}}
-{{ range .Site.Authors }}
-{{ with .twitter -}}
+{{- $title := .Title | default .Site.Title }}
+{{- $titleLength := 70 }}{{ with .Site.Params.twitter }}{{ if isset . "titlelength" }}{{ $titleLength = int (index . "titlelength") }}{{ end }}{{ end }}
+<meta name="twitter:title" content="{{ if gt $titleLength 0 }}{{ truncate $titleLength $title }}{{ else }}{{ $title }}{{ end }}"/>
+{{- /* The description length falls back to the Open Graph one. */}}
+{{- $descriptionLength := 200 }}{{ with .Site.Params.opengraph }}{{ if isset . "descriptionlength" }}{{ $descriptionLength = int (index . "descriptionlength") }}{{ end }}{{ end }}
+{{- with .Site.Params.twitter }}{{ if isset . "descriptionlength" }}{{ $descriptionLength = int (index . "descriptionlength") }}{{ end }}{{ end }}
+{{- $description := newScratch }}{{ template "__social_description" (dict "page" . "length" $descriptionLength "scratch" $description) }}
+{{- with $description.Get "description" }}
+<meta name="twitter:description" content="{{ . }}"/>
+{{- end }}
+{{- with .Site.Social.twitter }}
+<meta name="twitter:site" content="@{{ strings.TrimPrefix "@" . }}"/>
+{{- end }}
+{{- /* Creator precedence: the author profiles of the page's authors, the page's author params and the site's handle. */ -}}
+{{- $creators := slice }}
+{{- with .Params.authors }}{{ range cond (reflect.IsSlice .) . (slice .) }}{{ with index $.Site.Authors . }}{{ with .Social.twitter }}{{ $creators = $creators | append (strings.TrimPrefix "@" .) }}{{ end }}{{ end }}{{ end }}{{ end }}
+{{- if not $creators }}{{ with .Params.author }}
+{{- range cond (reflect.IsSlice .) . (slice .) }}{{ if reflect.IsMap . }}{{ with index . "twitter" }}{{ $creators = $creators | append (strings.TrimPrefix "@" .) }}{{ end }}{{ end }}{{ end }}
+{{- end }}{{ end }}
+{{- if not $creators }}{{ with .Site.Social.twitter }}{{ $creators = slice (strings.TrimPrefix "@" .) }}{{ end }}{{ end }}
+{{- range uniq $creators }}
 <meta name="twitter:creator" content="@{{ . }}"/>
-{{ end -}}
-{{ end -}}`},
+{{- end }}
+`},
 }
@@ -54,6 +54,12 @@ func init() {
 			[]string{"relref"},
 			[][2]string{},
 		)
+		ns.AddMethodMapping(ctx.ResolveURLs,
+			nil,
+			[][2]string{
+				{`{{ urls.ResolveURLs "https://example.org/post/" "<img src=\"a.jpg\">" }}`, `<img src="https://example.org/post/a.jpg">`},
+			},
+		)
 		ns.AddMethodMapping(ctx.URLize,
 			[]string{"urlize"},
 			[][2]string{},
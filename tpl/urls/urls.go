@@ -23,6 +23,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/urls"
 	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/transform/urlreplacers"
 	_errors "github.com/pkg/errors"
 	"github.com/russross/blackfriday"
 	"github.com/spf13/cast"
@@ -63,6 +64,30 @@ func (ns *Namespace) Parse(rawurl interface{}) (*url.URL, error) {
 	return url.Parse(s)
 }
 
+// ResolveURLs resolves the URLs in the src, href, action and srcset attributes
+// of the given HTML content against the absolute base URL, e.g. a page's
+// permalink.
+func (ns *Namespace) ResolveURLs(base, content interface{}) (template.HTML, error) {
+	b, err := cast.ToStringE(base)
+	if err != nil {
+		return "", _errors.Wrap(err, "Error in ResolveURLs")
+	}
+	s, err := cast.ToStringE(content)
+	if err != nil {
+		return "", _errors.Wrap(err, "Error in ResolveURLs")
+	}
+
+	u, err := url.Parse(b)
+	if err != nil {
+		return "", _errors.Wrap(err, "Error in ResolveURLs")
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("ResolveURLs requires an absolute base URL, got %q", b)
+	}
+
+	return template.HTML(urlreplacers.ResolveURLs(u, []byte(s))), nil
+}
+
 // RelURL takes a given string and prepends the relative path according to a
 // page's position in the project directory structure.
 func (ns *Namespace) RelURL(a interface{}) (template.HTML, error) {
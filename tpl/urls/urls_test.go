@@ -15,6 +15,7 @@ package urls
 
 import (
 	"fmt"
+	"html/template"
 	"net/url"
 	"testing"
 
@@ -66,3 +67,32 @@ func TestParse(t *testing.T) {
 		assert.Equal(t, test.expect, result, errMsg)
 	}
 }
+
+func TestResolveURLs(t *testing.T) {
+	t.Parallel()
+
+	for i, test := range []struct {
+		base    interface{}
+		content interface{}
+		expect  interface{}
+	}{
+		{"https://example.org/post/", `<img src="a.jpg" srcset="a.jpg 1x, //cdn.example.org/b.jpg 2x">`, template.HTML(`<img src="https://example.org/post/a.jpg" srcset="https://example.org/post/a.jpg 1x, https://cdn.example.org/b.jpg 2x">`)},
+		{"https://example.org/post/", template.HTML(`<a href="/about/">About</a>`), template.HTML(`<a href="https://example.org/about/">About</a>`)},
+		// errors
+		{"/post/", `<a href="/about/">About</a>`, false},
+		{tstNoStringer{}, `<a href="/about/">About</a>`, false},
+		{"https://example.org/post/", tstNoStringer{}, false},
+	} {
+		errMsg := fmt.Sprintf("[%d] %v", i, test)
+
+		result, err := ns.ResolveURLs(test.base, test.content)
+
+		if b, ok := test.expect.(bool); ok && !b {
+			require.Error(t, err, errMsg)
+			continue
+		}
+
+		require.NoError(t, err, errMsg)
+		assert.Equal(t, test.expect, result, errMsg)
+	}
+}
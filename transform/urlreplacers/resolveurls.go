@@ -0,0 +1,111 @@
+// Copyright 2019 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package urlreplacers
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ResolveURLs resolves the URLs in the src, href, action and srcset attributes
+// of the given HTML against base, e.g. a page's permalink, so relative, root
+// relative and protocol relative URLs become absolute.
+//
+// Unlike the absURL transformers, which look for a few quoted attribute
+// prefixes in the byte stream, this tokenizes the HTML, so attributes are
+// found whatever their quoting, case or spacing, and never in text, comments
+// or script content. Tags with URLs to resolve are written back normalized,
+// everything else is kept as is.
+func ResolveURLs(base *url.URL, content []byte) []byte {
+	var buf bytes.Buffer
+	z := html.NewTokenizer(bytes.NewReader(content))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// The content is read from memory, so this is io.EOF.
+			return buf.Bytes()
+		}
+
+		// The tokenizer lower cases tag and attribute names in its buffer,
+		// so keep a copy of the original.
+		raw := append([]byte(nil), z.Raw()...)
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			buf.Write(raw)
+			continue
+		}
+
+		tok := z.Token()
+		if tt == html.SelfClosingTagToken && len(tok.Attr) > 0 && bytes.HasSuffix(raw, []byte(tok.Attr[len(tok.Attr)-1].Val+">")) {
+			// In e.g. <a href=/about/> the slash is part of the unquoted
+			// value, but the tokenizer also reports the tag as self-closing.
+			tok.Type = html.StartTagToken
+		}
+		changed := false
+		for i, attr := range tok.Attr {
+			var v string
+			switch attr.Key {
+			case "src", "href", "action":
+				v = resolveURL(base, attr.Val)
+			case "srcset":
+				v = resolveSrcset(base, attr.Val)
+			default:
+				continue
+			}
+			if v != attr.Val {
+				tok.Attr[i].Val = v
+				changed = true
+			}
+		}
+
+		if changed {
+			buf.WriteString(tok.String())
+		} else {
+			buf.Write(raw)
+		}
+	}
+}
+
+// resolveURL resolves u against base. Empty and invalid URLs are left as is.
+func resolveURL(base *url.URL, u string) string {
+	s := strings.TrimSpace(u)
+	if s == "" {
+		return u
+	}
+	ref, err := url.Parse(s)
+	if err != nil {
+		return u
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// resolveSrcset resolves the URLs of the comma separated image candidates
+// in a srcset attribute.
+func resolveSrcset(base *url.URL, srcset string) string {
+	if strings.TrimSpace(srcset) == "" {
+		return srcset
+	}
+	candidates := strings.Split(srcset, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 {
+			fields[0] = resolveURL(base, fields[0])
+		}
+		candidates[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
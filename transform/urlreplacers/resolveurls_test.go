@@ -0,0 +1,64 @@
+// Copyright 2019 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package urlreplacers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestResolveURLs(t *testing.T) {
+	base, err := url.Parse("https://example.org/blog/post/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []test{
+		{
+			content:  `<a href="/about/">About</a> <a href='other/'>Other</a> <a href=../up/>Up</a>`,
+			expected: `<a href="https://example.org/about/">About</a> <a href="https://example.org/blog/post/other/">Other</a> <a href="https://example.org/blog/up/">Up</a>`,
+		},
+		{
+			content:  `<A HREF = "/about/" class=nav>About</A> <img data-src="lazy.jpg" SRC= 'a.jpg' />`,
+			expected: `<a href="https://example.org/about/" class="nav">About</A> <img data-src="lazy.jpg" src="https://example.org/blog/post/a.jpg"/>`,
+		},
+		{
+			content:  `<img src="//cdn.example.com/img.jpg"> <a href="https://example.com/">Abs</a> <a href="#note">Note</a>`,
+			expected: `<img src="https://cdn.example.com/img.jpg"> <a href="https://example.com/">Abs</a> <a href="https://example.org/blog/post/#note">Note</a>`,
+		},
+		{
+			content:  `<img srcset="small.jpg 200w,/img/big.jpg 700w, //cdn.example.com/huge.jpg 2x" src="small.jpg">`,
+			expected: `<img srcset="https://example.org/blog/post/small.jpg 200w, https://example.org/img/big.jpg 700w, https://cdn.example.com/huge.jpg 2x" src="https://example.org/blog/post/small.jpg">`,
+		},
+		{
+			content:  `<img srcset=small.jpg src=small.jpg alt="A &amp; B">`,
+			expected: `<img srcset="https://example.org/blog/post/small.jpg" src="https://example.org/blog/post/small.jpg" alt="A &amp; B">`,
+		},
+		{
+			content:  `<img srcset="" src=""> <a href="mailto:me@example.org">Mail</a>`,
+			expected: `<img srcset="" src=""> <a href="mailto:me@example.org">Mail</a>`,
+		},
+		{
+			content:  `<p>Use href="/about/" in <code>&lt;a href="/x"&gt;</code>.</p><!-- <a href="/c"> --><script>var s = 'src="/s.js"';</script>`,
+			expected: `<p>Use href="/about/" in <code>&lt;a href="/x"&gt;</code>.</p><!-- <a href="/c"> --><script>var s = 'src="/s.js"';</script>`,
+		},
+		{content: replace3, expected: replace3},
+		{content: replace5, expected: replace5},
+	} {
+		got := string(ResolveURLs(base, []byte(test.content)))
+		if got != test.expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", test.expected, got)
+		}
+	}
+}